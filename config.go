@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// config holds settings that rarely change between runs. It's read from a
+// JSON file, e.g.
+//
+//	{
+//		"favorite_theaters": ["Michigan Theater", "State Theatre"],
+//		"exclude_theaters": ["Emagine"]
+//	}
+type config struct {
+	// FavoriteTheaters are checked first and listed first. See
+	// crawler.Request.FavoriteTheaters.
+	FavoriteTheaters []string `json:"favorite_theaters"`
+	// ExcludeTheaters are never checked, as with --exclude-theater.
+	ExcludeTheaters []string `json:"exclude_theaters"`
+}
+
+// defaultConfigPath returns where the config file lives by default, or "" if
+// there's no user config directory.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "mseater", "config.json")
+}
+
+// loadConfig reads the config file at path. A missing file is an empty config.
+func loadConfig(path string) (config, error) {
+	var cfg config
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	} else if err != nil {
+		return config{}, fmt.Errorf("failed to read config: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return config{}, fmt.Errorf("failed to parse config %q: %w", path, err)
+	}
+	return cfg, nil
+}
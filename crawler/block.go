@@ -0,0 +1,84 @@
+package crawler
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	playwright "github.com/playwright-community/playwright-go"
+)
+
+// blockPhrases show up on the pages sites serve instead of the one we asked
+// for: bot challenges, "access denied" pages and waiting room queues.
+var blockPhrases = []string{
+	"access denied",
+	"are you a robot",
+	"verify you are human",
+	"verify you are a human",
+	"unusual traffic",
+	"pardon our interruption",
+	"request unsuccessful",
+	"too many requests",
+	"you are now in line",
+	"you're in line",
+	"waiting room",
+}
+
+// blockRegex matches any of blockPhrases.
+var blockRegex = func() *regexp.Regexp {
+	quoted := make([]string, 0, len(blockPhrases))
+	for _, phrase := range blockPhrases {
+		quoted = append(quoted, regexp.QuoteMeta(phrase))
+	}
+	return regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+}()
+
+// blockReason returns the phrase that makes a page with the given title and
+// text look like a block page, or "" if it looks normal.
+func blockReason(title, text string) string {
+	if match := blockRegex.FindString(title); match != "" {
+		return strings.ToLower(match)
+	}
+	return strings.ToLower(blockRegex.FindString(text))
+}
+
+// checkBlocked returns an error wrapping ErrBlocked if page looks like a block
+// page. Pages that can't be read are assumed to be fine, leaving it to the
+// caller to notice that they're broken.
+func checkBlocked(page playwright.Page) error {
+	title, err := page.Title()
+	if err != nil {
+		return nil
+	}
+	text, err := page.Evaluate("() => document.body ? document.body.innerText : ''")
+	if err != nil {
+		return nil
+	}
+	textStr, _ := text.(string)
+	if reason := blockReason(title, textStr); reason != "" {
+		return fmt.Errorf("%w: page %q looks like a block page (it says %q)", ErrBlocked, page.URL(), reason)
+	}
+	return nil
+}
+
+// waitForSeats waits up to timeout for page, at link, to show either a seat
+// map or a block page, so that we don't sit around for the whole timeout when
+// the site won't show us seats. Seat maps can mention a block phrase in passing,
+// so a page showing seats is never reported as blocked.
+func waitForSeats(page playwright.Page, link string, timeout time.Duration) error {
+	timeoutMS := float64(timeout.Milliseconds())
+	firstSeat := page.Locator(".seat-map__seat").First()
+	blocked := page.GetByText(blockRegex).First()
+	waitErr := firstSeat.Or(blocked).First().WaitFor(playwright.LocatorWaitForOptions{Timeout: &timeoutMS})
+	if visible, err := firstSeat.IsVisible(); err == nil && visible {
+		return nil
+	}
+	if err := checkBlocked(page); err != nil {
+		return err
+	}
+	if waitErr != nil {
+		return fmt.Errorf("%w on page %q: %w", ErrSeatMapTimeout, link, waitErr)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBlockReason(t *testing.T) {
+	tcs := []struct {
+		name  string
+		title string
+		text  string
+		want  string
+	}{
+		{
+			name:  "search page",
+			title: "Movie Times near 48104 | Fandango",
+			text:  "AMC Ann Arbor 20\nSunny\nReserved seating\n7:00p 9:30p",
+		},
+		{
+			name:  "access denied title",
+			title: "Access Denied",
+			text:  "You don't have permission to access this page.",
+			want:  "access denied",
+		},
+		{
+			name:  "challenge text",
+			title: "fandango.com",
+			text:  "Please verify you are a human by completing the action below.",
+			want:  "verify you are a human",
+		},
+		{
+			name:  "queue",
+			title: "Fandango",
+			text:  "You Are Now In Line. Thank you for your patience.",
+			want:  "you are now in line",
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := blockReason(tc.title, tc.text); got != tc.want {
+				t.Errorf("blockReason(%q, %q) = %q, want %q", tc.title, tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWaitForSeats(t *testing.T) {
+	skipWithoutBrowser(t)
+	client, err := NewClient(ClientOptions{})
+	if err != nil {
+		t.Fatalf("NewClient() returned error: %v", err)
+	}
+	defer closeClient(client)
+
+	tcs := []struct {
+		name    string
+		content string
+		want    error
+	}{
+		{
+			name:    "seats",
+			content: `<div class="seat-map__seat">A1</div>`,
+		},
+		{
+			name:    "seats mentioning a block phrase",
+			content: `<div class="seat-map__seat">A1</div><p>Tickets go fast, so there may be a waiting room.</p>`,
+		},
+		{
+			name:    "block page",
+			content: `<h1>Access Denied</h1>`,
+			want:    ErrBlocked,
+		},
+		{
+			name:    "neither",
+			content: `<p>Loading...</p>`,
+			want:    ErrSeatMapTimeout,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, page, release, err := client.newPage(context.Background())
+			if err != nil {
+				t.Fatalf("newPage() returned error: %v", err)
+			}
+			defer release()
+			if err := page.SetContent(tc.content); err != nil {
+				t.Fatalf("failed to set page content: %v", err)
+			}
+			err = waitForSeats(page, "https://example.com", time.Second)
+			if tc.want == nil {
+				if err != nil {
+					t.Fatalf("waitForSeats() = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("waitForSeats() = %v, want %v", err, tc.want)
+			}
+		})
+	}
+}
@@ -1,26 +1,55 @@
 package crawler
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"time"
 
 	playwright "github.com/playwright-community/playwright-go"
 )
 
-// Pages are rate limited globally.
-var started bool
-
 type rateLimitedPage struct {
 	playwright.Page
-	interval DurationRange
+	// ctx bounds waits on pacer, since Page's methods don't take one.
+	ctx    context.Context
+	pacer  *pacer
+	logger *slog.Logger
 }
 
 func (rlp *rateLimitedPage) Goto(url string, options ...playwright.PageGotoOptions) (playwright.Response, error) {
-	if started {
-		time.Sleep(rlp.interval.Random())
-	} else {
-		started = true
+	if err := rlp.pacer.wait(rlp.ctx); err != nil {
+		return nil, err
+	}
+	rlp.logger.Debug("visiting", "URL", url)
+	start := time.Now()
+	resp, err := rlp.Page.Goto(url, options...)
+	if err != nil {
+		return resp, err
+	}
+	if resp == nil {
+		return resp, nil
+	}
+	rlp.pacer.loaded(resp.Status(), time.Since(start))
+	return resp, statusError(url, resp.Status(), rlp.pacer.adaptive)
+}
+
+// statusError returns the error, if any, for a page at url that loaded with
+// status.
+//
+// Sites tend to say "go away" with these. An adaptive pacer takes "too many
+// requests" and "service unavailable" as a cue to slow down and try again
+// rather than a block. Either way the page isn't worth parsing.
+func statusError(url string, status int, adaptive bool) error {
+	switch {
+	case status == http.StatusForbidden:
+		return fmt.Errorf("%w: %q returned HTTP %d", ErrBlocked, url, status)
+	case adaptive && (status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable):
+		return fmt.Errorf("%w: %q returned HTTP %d", errRateLimited, url, status)
+	case status == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %q returned HTTP %d", ErrBlocked, url, status)
+	default:
+		return nil
 	}
-	fmt.Printf("Visiting %s\n", url)
-	return rlp.Page.Goto(url, options...)
 }
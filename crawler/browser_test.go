@@ -0,0 +1,62 @@
+package crawler
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestStatusError(t *testing.T) {
+	tcs := []struct {
+		name     string
+		status   int
+		adaptive bool
+		want     error
+	}{
+		{
+			name:   "ok",
+			status: http.StatusOK,
+		},
+		{
+			name:     "forbidden",
+			status:   http.StatusForbidden,
+			adaptive: true,
+			want:     ErrBlocked,
+		},
+		{
+			name:   "too many requests",
+			status: http.StatusTooManyRequests,
+			want:   ErrBlocked,
+		},
+		{
+			name:     "too many requests when adaptive",
+			status:   http.StatusTooManyRequests,
+			adaptive: true,
+			want:     errRateLimited,
+		},
+		{
+			name:     "service unavailable when adaptive",
+			status:   http.StatusServiceUnavailable,
+			adaptive: true,
+			want:     errRateLimited,
+		},
+		{
+			name:   "service unavailable",
+			status: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := statusError("https://example.com", tc.status, tc.adaptive)
+			if tc.want == nil {
+				if err != nil {
+					t.Fatalf("statusError(%d, %t) = %v, want nil", tc.status, tc.adaptive, err)
+				}
+				return
+			}
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("statusError(%d, %t) = %v, want %v", tc.status, tc.adaptive, err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package crawler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Kinds of cache entries.
+const (
+	cacheSearch       = "search"
+	cacheLayout       = "layout"
+	cacheAvailability = "availability"
+)
+
+// CacheOptions configures an on-disk cache of crawl results. Each kind of
+// result has its own TTL, and a TTL of zero disables caching that kind.
+type CacheOptions struct {
+	// Dir holds the cache.
+	Dir string
+	// SearchTTL is how long the showings found on a search page stay fresh.
+	SearchTTL time.Duration
+	// LayoutTTL is how long a showing's seat layout stays fresh. Layouts are
+	// cached per showing, so they're only reused when the same showing is
+	// checked again, e.g. by a retry or a later run.
+	LayoutTTL time.Duration
+	// AvailabilityTTL is how long a showing's reserved seats stay fresh.
+	AvailabilityTTL time.Duration
+}
+
+// DefaultCacheOptions returns CacheOptions for a cache in dir with reasonable
+// TTLs.
+func DefaultCacheOptions(dir string) CacheOptions {
+	return CacheOptions{
+		Dir:             dir,
+		SearchTTL:       time.Hour,
+		LayoutTTL:       24 * time.Hour,
+		AvailabilityTTL: 10 * time.Minute,
+	}
+}
+
+// A cache stores crawl results on disk as one JSON file per entry. A nil
+// cache stores nothing. Expired entries are removed when the cache is opened.
+type cache struct {
+	opts CacheOptions
+	now  func() time.Time
+}
+
+func newCache(opts *CacheOptions) *cache {
+	if opts == nil || opts.Dir == "" {
+		return nil
+	}
+	ch := &cache{opts: *opts, now: time.Now}
+	ch.prune()
+	return ch
+}
+
+// prune removes expired entries. It's best effort: entries that can't be
+// removed are left for next time.
+func (ch *cache) prune() {
+	for _, kind := range []string{cacheSearch, cacheLayout, cacheAvailability} {
+		ttl := ch.ttl(kind)
+		if ttl <= 0 {
+			continue
+		}
+		dir := filepath.Join(ch.opts.Dir, kind)
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			if filepath.Ext(file.Name()) != ".json" {
+				continue
+			}
+			// Entries are written once, so the modification time is
+			// when they were stored.
+			info, err := file.Info()
+			if err == nil && ch.now().Sub(info.ModTime()) > ttl {
+				os.Remove(filepath.Join(dir, file.Name()))
+			}
+		}
+	}
+}
+
+type cacheEntry struct {
+	Key    string          `json:"key"`
+	Stored time.Time       `json:"stored"`
+	Value  json.RawMessage `json:"value"`
+}
+
+func (ch *cache) ttl(kind string) time.Duration {
+	switch kind {
+	case cacheSearch:
+		return ch.opts.SearchTTL
+	case cacheLayout:
+		return ch.opts.LayoutTTL
+	case cacheAvailability:
+		return ch.opts.AvailabilityTTL
+	default:
+		panic(fmt.Sprintf("unknown cache kind %q", kind))
+	}
+}
+
+func (ch *cache) path(kind, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(ch.opts.Dir, kind, hex.EncodeToString(sum[:])+".json")
+}
+
+// get loads the entry of the given kind for key into value. It returns
+// whether a fresh entry was found. Unreadable entries are treated as missing.
+func (ch *cache) get(kind, key string, value any) bool {
+	if ch == nil || ch.ttl(kind) <= 0 {
+		return false
+	}
+	data, err := os.ReadFile(ch.path(kind, key))
+	if err != nil {
+		return false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key {
+		return false
+	}
+	if ch.now().Sub(entry.Stored) > ch.ttl(kind) {
+		return false
+	}
+	return json.Unmarshal(entry.Value, value) == nil
+}
+
+// put stores value as the entry of the given kind for key.
+func (ch *cache) put(kind, key string, value any) error {
+	if ch == nil || ch.ttl(kind) <= 0 {
+		return nil
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	data, err := json.Marshal(cacheEntry{Key: key, Stored: ch.now(), Value: raw})
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	return writeFileAtomic(ch.path(kind, key), data)
+}
+
+// writeFileAtomic writes data to path such that readers never see a partial
+// file.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %q: %w", tmp.Name(), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %q: %w", tmp.Name(), err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %q: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package crawler
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	opts := DefaultCacheOptions(t.TempDir())
+	opts.LayoutTTL = 0
+	ch := newCache(&opts)
+	now := time.Date(2026, 10, 18, 19, 0, 0, 0, time.UTC)
+	ch.now = func() time.Time { return now }
+
+	const key = "https://www.fandango.com/48104_movietimes?date=2026-10-18"
+	want := []Showing{{Link: "https://example.com/seats", Theater: "State Theatre", When: now}}
+	if err := ch.put(cacheSearch, key, want); err != nil {
+		t.Fatalf("put() returned error: %v", err)
+	}
+
+	var got []Showing
+	if !ch.get(cacheSearch, key, &got) {
+		t.Fatalf("get() found nothing after put()")
+	}
+	if len(got) != 1 || got[0].Link != want[0].Link || got[0].Theater != want[0].Theater || !got[0].When.Equal(want[0].When) {
+		t.Errorf("get() = %+v, want %+v", got, want)
+	}
+	if ch.get(cacheSearch, key+"&other", &got) {
+		t.Errorf("get() found an entry for a different key")
+	}
+	if ch.get(cacheAvailability, key, &got) {
+		t.Errorf("get() found an entry of a different kind")
+	}
+
+	// Entries expire.
+	now = now.Add(opts.SearchTTL + time.Second)
+	if ch.get(cacheSearch, key, &got) {
+		t.Errorf("get() found an expired entry")
+	}
+
+	// Kinds with a zero TTL aren't cached.
+	if err := ch.put(cacheLayout, key, want); err != nil {
+		t.Fatalf("put() returned error: %v", err)
+	}
+	if _, err := os.Stat(ch.path(cacheLayout, key)); !os.IsNotExist(err) {
+		t.Errorf("put() wrote an entry with a zero TTL")
+	}
+
+	// Corrupt entries are ignored.
+	if err := os.WriteFile(ch.path(cacheSearch, key), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(-opts.SearchTTL)
+	if ch.get(cacheSearch, key, &got) {
+		t.Errorf("get() found a corrupt entry")
+	}
+}
+
+func TestCachePrune(t *testing.T) {
+	opts := DefaultCacheOptions(t.TempDir())
+	ch := newCache(&opts)
+	stored := time.Now()
+	for _, key := range []string{"fresh", "expired"} {
+		if err := ch.put(cacheLayout, key, key); err != nil {
+			t.Fatalf("put() returned error: %v", err)
+		}
+	}
+	expired := stored.Add(-opts.LayoutTTL - time.Hour)
+	if err := os.Chtimes(ch.path(cacheLayout, "expired"), expired, expired); err != nil {
+		t.Fatal(err)
+	}
+
+	ch = newCache(&opts)
+	if _, err := os.Stat(ch.path(cacheLayout, "expired")); !os.IsNotExist(err) {
+		t.Errorf("expired entry wasn't pruned")
+	}
+	var got string
+	if !ch.get(cacheLayout, "fresh", &got) || got != "fresh" {
+		t.Errorf("get() = %q after pruning, want the fresh entry", got)
+	}
+}
+
+func TestNilCache(t *testing.T) {
+	var ch *cache
+	if err := ch.put(cacheSearch, "key", "value"); err != nil {
+		t.Errorf("put() on nil cache returned error: %v", err)
+	}
+	var value string
+	if ch.get(cacheSearch, "key", &value) {
+		t.Errorf("get() on nil cache found an entry")
+	}
+}
+
+func TestSeatMapRoundTrip(t *testing.T) {
+	want := seatMap{
+		seats: []seat{
+			{row: 0, col: 0, reserved: true},
+			{row: 0, col: 1},
+			{row: 1, col: 0},
+			{row: 1, col: 1},
+			{row: 1, col: 2, reserved: true},
+		},
+		maxRow: 1,
+		maxCol: 2,
+	}
+	got, ok := newSeatMap(want.layout(), want.availability())
+	if !ok {
+		t.Fatalf("newSeatMap() rejected its own layout and availability")
+	}
+	if got.maxRow != want.maxRow || got.maxCol != want.maxCol || len(got.seats) != len(want.seats) {
+		t.Fatalf("newSeatMap() = %+v, want %+v", got, want)
+	}
+	for i := range want.seats {
+		if got.seats[i] != want.seats[i] {
+			t.Errorf("seat %d is %+v, want %+v", i, got.seats[i], want.seats[i])
+		}
+	}
+
+	// Availability for a different number of seats means the layout changed.
+	availability := want.availability()
+	availability.Reserved = availability.Reserved[1:]
+	if _, ok := newSeatMap(want.layout(), availability); ok {
+		t.Errorf("newSeatMap() accepted mismatched layout and availability")
+	}
+}
@@ -0,0 +1,82 @@
+package crawler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// A checkpoint records a crawl's showings and each one's verdict as it's
+// found, so that an interrupted crawl can pick up where it stopped. A nil
+// checkpoint records nothing.
+type checkpoint struct {
+	path string
+
+	// Search identifies the search, so that a checkpoint isn't resumed by a
+	// different one.
+	Search   string    `json:"search"`
+	Showings []Showing `json:"showings"`
+}
+
+// loadCheckpoint returns the checkpoint at path for search. If there's no
+// checkpoint there yet, it returns an empty one. If path is empty, it returns
+// nil.
+func loadCheckpoint(path, search string) (*checkpoint, error) {
+	if path == "" {
+		return nil, nil
+	}
+	cp := &checkpoint{path: path, Search: search}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint %q: %w", path, err)
+	}
+	if cp.Search != search {
+		return nil, fmt.Errorf("checkpoint %q is for a different search (%s), not %s", path, cp.Search, search)
+	}
+	return cp, nil
+}
+
+// resumable returns whether cp holds showings from an earlier crawl.
+func (cp *checkpoint) resumable() bool {
+	return cp != nil && len(cp.Showings) > 0
+}
+
+// start records the showings a crawl is about to check.
+func (cp *checkpoint) start(showings []Showing) error {
+	if cp == nil {
+		return nil
+	}
+	cp.Showings = showings
+	return cp.save()
+}
+
+// record updates the verdict of showing.
+func (cp *checkpoint) record(showing Showing) error {
+	if cp == nil {
+		return nil
+	}
+	for i := range cp.Showings {
+		if cp.Showings[i].Link == showing.Link {
+			cp.Showings[i].Verdict = showing.Verdict
+			return cp.save()
+		}
+	}
+	return fmt.Errorf("showing %q isn't in checkpoint", showing.Link)
+}
+
+func (cp *checkpoint) save() error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %w", err)
+	}
+	if err := writeFileAtomic(cp.path, data); err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package crawler
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	const search = `zip=48104 date=2026-10-18 title="sunny" seats=2`
+
+	cp, err := loadCheckpoint(path, search)
+	if err != nil {
+		t.Fatalf("loadCheckpoint() returned error: %v", err)
+	}
+	if cp.resumable() {
+		t.Fatalf("new checkpoint is resumable")
+	}
+	showings := []Showing{
+		{Link: "https://example.com/1", Theater: "State Theatre"},
+		{Link: "https://example.com/2", Theater: "Michigan Theater"},
+	}
+	if err := cp.start(showings); err != nil {
+		t.Fatalf("start() returned error: %v", err)
+	}
+	done := showings[1]
+	done.Verdict = VerdictGood
+	if err := cp.record(done); err != nil {
+		t.Fatalf("record() returned error: %v", err)
+	}
+	if err := cp.record(Showing{Link: "https://example.com/3"}); err == nil {
+		t.Errorf("record() of an unknown showing returned no error")
+	}
+
+	// Resume.
+	cp, err = loadCheckpoint(path, search)
+	if err != nil {
+		t.Fatalf("loadCheckpoint() returned error: %v", err)
+	}
+	if !cp.resumable() {
+		t.Fatalf("saved checkpoint isn't resumable")
+	}
+	if len(cp.Showings) != 2 || cp.Showings[0].Verdict != VerdictUnknown || cp.Showings[1].Verdict != VerdictGood {
+		t.Errorf("resumed showings are %+v", cp.Showings)
+	}
+
+	// A different search can't use it.
+	if _, err := loadCheckpoint(path, `zip=10001 date=2026-10-18 title="sunny" seats=2`); err == nil {
+		t.Errorf("loadCheckpoint() for a different search returned no error")
+	}
+}
+
+func TestNilCheckpoint(t *testing.T) {
+	cp, err := loadCheckpoint("", "search")
+	if err != nil || cp != nil {
+		t.Fatalf("loadCheckpoint() with no path = (%v, %v), want (nil, nil)", cp, err)
+	}
+	if cp.resumable() {
+		t.Errorf("nil checkpoint is resumable")
+	}
+	if err := cp.start([]Showing{{Link: "https://example.com/1"}}); err != nil {
+		t.Errorf("start() on nil checkpoint returned error: %v", err)
+	}
+	if err := cp.record(Showing{Link: "https://example.com/1"}); err != nil {
+		t.Errorf("record() on nil checkpoint returned error: %v", err)
+	}
+}
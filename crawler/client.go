@@ -0,0 +1,272 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+
+	playwright "github.com/playwright-community/playwright-go"
+)
+
+// maxIdleContexts is how many warm browser contexts a Client keeps around
+// between pages.
+const maxIdleContexts = 2
+
+// Browser engines a Client can use.
+const (
+	Chromium = "chromium"
+	Firefox  = "firefox"
+	WebKit   = "webkit"
+)
+
+// ClientOptions configures a Client.
+type ClientOptions struct {
+	// Browser is the browser engine to use: Chromium, Firefox or WebKit. If
+	// empty, Chromium is used.
+	Browser string
+	// Headful shows the browser window instead of running headless. Useful
+	// for watching what the crawler does.
+	Headful bool
+	// ExecutablePath, if set, is a browser executable to launch instead of
+	// the one bundled with Playwright.
+	ExecutablePath string
+	// CDPEndpoint, if set, is the endpoint of an already running Chromium to
+	// attach to instead of launching a browser, e.g. "http://localhost:9222".
+	CDPEndpoint string
+	// Proxy, if set, is the proxy every page is loaded through.
+	Proxy *Proxy
+	// Cache, if set, configures an on-disk cache of search results and seat
+	// maps.
+	Cache *CacheOptions
+
+	// StorageState, if set, is a file holding cookies and local storage.
+	// It's loaded into new browser contexts if it exists, and saved when the
+	// Client is closed.
+	StorageState string
+	// Logger receives logs about the Client itself. If nil, slog.Default()
+	// is used.
+	Logger *slog.Logger
+}
+
+// A Client owns a browser and reuses it, along with warm browser contexts,
+// across crawls. The package-level functions start a new browser for each
+// call, so long-running programs should prefer a Client.
+//
+// A Client is safe for concurrent use, but crawls run one at a time so that
+// the site sees a polite request rate.
+type Client struct {
+	opts   ClientOptions
+	logger *slog.Logger
+	pw     *playwright.Playwright
+	cache  *cache
+
+	// crawlMu is held for the duration of each crawl, except while
+	// CrawlStream yields. It guards browser and pacer.
+	crawlMu sync.Mutex
+	browser playwright.Browser
+	pacer   *pacer
+
+	// mu guards the fields below.
+	mu     sync.Mutex
+	idle   []playwright.BrowserContext
+	closed bool
+}
+
+func (opts *ClientOptions) browser() string {
+	if opts.Browser == "" {
+		return Chromium
+	}
+	return opts.Browser
+}
+
+func (opts *ClientOptions) validate() error {
+	switch opts.browser() {
+	case Chromium, Firefox, WebKit:
+	default:
+		return fmt.Errorf("unknown browser %q: must be one of %q, %q or %q", opts.Browser, Chromium, Firefox, WebKit)
+	}
+	if opts.CDPEndpoint == "" {
+		return nil
+	}
+	switch {
+	case opts.browser() != Chromium:
+		return fmt.Errorf("only %q can be attached to via a CDP endpoint, not %q", Chromium, opts.Browser)
+	case opts.ExecutablePath != "":
+		return fmt.Errorf("can't both attach to a CDP endpoint and launch %q", opts.ExecutablePath)
+	case opts.Headful:
+		return fmt.Errorf("headful has no effect when attaching to a CDP endpoint")
+	}
+	return nil
+}
+
+// NewClient starts Playwright and a browser. Call Close to stop them.
+func NewClient(opts ClientOptions) (*Client, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	// Boot up playwright.
+	runOpts := &playwright.RunOptions{SkipInstallBrowsers: true}
+	if err := playwright.Install(runOpts); err != nil {
+		return nil, fmt.Errorf("failed to install playwright drivers: %w", err)
+	}
+	pw, err := playwright.Run()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run playwright: %w", err)
+	}
+	browser, err := launch(pw, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w, pw.Stop(): %v", err, pw.Stop())
+	}
+
+	return &Client{
+		opts:    opts,
+		logger:  logger,
+		pw:      pw,
+		browser: browser,
+		cache:   newCache(opts.Cache),
+		pacer:   newPacer(DurationRange{}, false /* adaptive */, logger),
+	}, nil
+}
+
+// launch launches or attaches to the browser described by opts.
+func launch(pw *playwright.Playwright, opts ClientOptions) (playwright.Browser, error) {
+	if opts.CDPEndpoint != "" {
+		browser, err := pw.Chromium.ConnectOverCDP(opts.CDPEndpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to attach to browser at %q: %w", opts.CDPEndpoint, err)
+		}
+		return browser, nil
+	}
+
+	var browserType playwright.BrowserType
+	switch opts.browser() {
+	case Chromium:
+		browserType = pw.Chromium
+	case Firefox:
+		browserType = pw.Firefox
+	case WebKit:
+		browserType = pw.WebKit
+	}
+	launchOpts := playwright.BrowserTypeLaunchOptions{Headless: playwright.Bool(!opts.Headful)}
+	if opts.ExecutablePath != "" {
+		launchOpts.ExecutablePath = playwright.String(opts.ExecutablePath)
+	}
+	browser, err := browserType.Launch(launchOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch %s (do you have NPM and NPX installed?): %w", opts.browser(), err)
+	}
+	return browser, nil
+}
+
+// Close saves the storage state, if configured, and shuts down the browser
+// and Playwright.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	idle := c.idle
+	c.idle = nil
+	c.mu.Unlock()
+
+	var errs []error
+	if c.opts.StorageState != "" && len(idle) > 0 {
+		if _, err := idle[0].StorageState(c.opts.StorageState); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save storage state: %w", err))
+		}
+	}
+	for _, browserCtx := range idle {
+		if err := browserCtx.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close browser context: %w", err))
+		}
+	}
+	if err := c.browser.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to stop browser: %w", err))
+	}
+	if err := c.pw.Stop(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to stop playwright: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// newPage returns a new page in a warm browser context. The caller must call
+// release when done with the page. If ctx is done before then, the browser
+// context is closed to abort whatever the page is doing.
+func (c *Client) newPage(ctx context.Context) (browserCtx playwright.BrowserContext, page playwright.Page, release func(), err error) {
+	browserCtx, err = c.acquireContext()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	page, err = browserCtx.NewPage()
+	if err != nil {
+		_ = browserCtx.Close()
+		return nil, nil, nil, fmt.Errorf("failed to create page: %w", err)
+	}
+	stop := context.AfterFunc(ctx, func() { _ = browserCtx.Close() })
+	release = func() {
+		_ = page.Close()
+		// If the AfterFunc already ran, the context is closed.
+		if stop() {
+			c.releaseContext(browserCtx)
+		}
+	}
+	return browserCtx, page, release, nil
+}
+
+func (c *Client) acquireContext() (playwright.BrowserContext, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil, errors.New("client is closed")
+	}
+	if n := len(c.idle); n > 0 {
+		browserCtx := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		return browserCtx, nil
+	}
+
+	browserCtx, err := c.browser.NewContext(c.opts.contextOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create context: %w", err)
+	}
+	return browserCtx, nil
+}
+
+// contextOptions returns the options for new browser contexts.
+func (opts *ClientOptions) contextOptions() playwright.BrowserNewContextOptions {
+	var ctxOpts playwright.BrowserNewContextOptions
+	// Our user agent is Chrome's, which would look odd coming from another
+	// engine.
+	if opts.browser() == Chromium {
+		ctxOpts.UserAgent = playwright.String(userAgent)
+	}
+	ctxOpts.Proxy = opts.Proxy.playwright()
+	if opts.StorageState != "" {
+		if _, err := os.Stat(opts.StorageState); err == nil {
+			ctxOpts.StorageStatePath = playwright.String(opts.StorageState)
+		}
+	}
+	return ctxOpts
+}
+
+func (c *Client) releaseContext(browserCtx playwright.BrowserContext) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed || len(c.idle) >= maxIdleContexts {
+		if err := browserCtx.Close(); err != nil {
+			c.logger.Info("failed to close browser context", "err", err)
+		}
+		return
+	}
+	c.idle = append(c.idle, browserCtx)
+}
@@ -0,0 +1,60 @@
+package crawler
+
+import (
+	"testing"
+)
+
+func TestClientOptionsValidate(t *testing.T) {
+	tcs := []struct {
+		name        string
+		opts        ClientOptions
+		expectError bool
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name: "firefox",
+			opts: ClientOptions{Browser: Firefox, Headful: true},
+		},
+		{
+			name: "system browser",
+			opts: ClientOptions{Browser: Chromium, ExecutablePath: "/usr/bin/chromium"},
+		},
+		{
+			name: "cdp",
+			opts: ClientOptions{CDPEndpoint: "http://localhost:9222"},
+		},
+		{
+			name:        "unknown browser",
+			opts:        ClientOptions{Browser: "netscape"},
+			expectError: true,
+		},
+		{
+			name:        "cdp with webkit",
+			opts:        ClientOptions{Browser: WebKit, CDPEndpoint: "http://localhost:9222"},
+			expectError: true,
+		},
+		{
+			name:        "cdp with executable",
+			opts:        ClientOptions{CDPEndpoint: "http://localhost:9222", ExecutablePath: "/usr/bin/chromium"},
+			expectError: true,
+		},
+		{
+			name:        "cdp with headful",
+			opts:        ClientOptions{CDPEndpoint: "http://localhost:9222", Headful: true},
+			expectError: true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.opts.validate()
+			if tc.expectError && err == nil {
+				t.Fatalf("expected error, but none found")
+			}
+			if !tc.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
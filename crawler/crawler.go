@@ -3,9 +3,11 @@ package crawler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"iter"
 	"log/slog"
-	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -18,15 +20,52 @@ import (
 // TODO: Try something besides playwright. A native Go library might work better.
 
 const (
-	retries   = 3
-	userAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/125.0.0.0 Safari/537.36"
+	// seatSelector selects regular seats, skipping handicap and companion
+	// seats.
+	seatSelector = ".seat-map__seat:not(.wheelchair):not(.companion)"
+	userAgent    = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/125.0.0.0 Safari/537.36"
 )
 
 type Request struct {
-	// Title is a substring of the movie title.
-	Title string
-	// Date is the day to search showings for.
-	Date time.Time
+	// Titles are the movie titles to search for. Showings of movies
+	// matching any of them are crawled. If there are none, every movie
+	// matches.
+	Titles []string
+	// TitleMatch decides how Titles are matched.
+	TitleMatch TitleMatch
+	// AllMatches, if set, crawls every movie a title matches. Otherwise a
+	// title matching several different movies is an *AmbiguousTitleError.
+	AllMatches bool
+	// Dates are the days to search showings for. At least one is required.
+	Dates []time.Time
+	// After and Before, if nonzero, limit showings to those starting within
+	// that window. They're times of day, as the time since midnight.
+	After  time.Duration
+	Before time.Duration
+	// EndsBefore, if nonzero, limits showings to those ending by that time
+	// of day. Showings whose runtime isn't known are kept.
+	EndsBefore time.Duration
+	// Formats, if set, limits showings to those in a matching format. A
+	// format matches if it contains one of them, e.g. "dolby" matches
+	// FormatDolbyCinema.
+	Formats []string
+	// ExcludeFormats skips showings in a matching format.
+	ExcludeFormats []string
+	// Theaters, if set, limits showings to theaters whose names contain one
+	// of them, ignoring case and punctuation.
+	Theaters []string
+	// ExcludeTheaters skips showings at matching theaters.
+	ExcludeTheaters []string
+	// MaxTheaters, if nonzero, limits how many theaters are searched. The
+	// search page lists the nearest theaters first, and more as they're
+	// asked for.
+	MaxTheaters int
+	// Radius, if nonzero, limits showings to theaters within that many
+	// miles of Zip. Theaters whose distance isn't known are kept.
+	Radius float64
+	// FavoriteTheaters are matched like Theaters. Showings at them are
+	// marked Favorite and checked first.
+	FavoriteTheaters []string
 	// Zip is the zip code to search near.
 	Zip string
 	// NumSeats is the number of seats to reserve.
@@ -35,11 +74,65 @@ type Request struct {
 	// ShowingLimit limits the number of showings to check. Useful for
 	// debugging.
 	ShowingLimit uint
-	// Retry is whether to retry seat crawling. The seating page is fairly slow
-	// to load and in some cases fails to render altogether.
-	Retry bool
+	// StopAfter, if nonzero, stops checking seats once that many good
+	// showings are found.
+	StopAfter uint
+	// Order decides which showings are checked first.
+	Order CrawlOrder
+	// PreferredTime is the time of day, since midnight, that OrderPreferred
+	// favors.
+	PreferredTime time.Duration
+	// Retry controls retrying of seat crawling. The seating page is fairly
+	// slow to load and in some cases fails to render altogether.
+	Retry RetryPolicy
 	// DurationRange is range of time to wait between HTTP requests.
 	RequestInterval DurationRange
+	// AdaptivePacing widens RequestInterval when the site seems unhappy, e.g.
+	// after consecutive failures, slow responses or HTTP 429/503, and eases
+	// back after a streak of successes. Showings that hit HTTP 429/503 are
+	// retried rather than treated as blocked.
+	AdaptivePacing bool
+
+	// Logger receives progress and diagnostic logs. If nil, slog.Default()
+	// is used.
+	Logger *slog.Logger
+	// Observer, if non-nil, receives events as the crawl progresses.
+	Observer Observer
+
+	// DiagnosticsDir, if set, is where a bundle of evidence (screenshot,
+	// HTML, console log) is saved for every page that fails.
+	DiagnosticsDir string
+	// Trace is whether diagnostics bundles include a Playwright trace.
+	Trace bool
+	// SnapshotDir, if set, is where a Snapshot of every seat map is saved.
+	SnapshotDir string
+	// Checkpoint, if set, is a file where the crawl's showings and their
+	// verdicts are saved as they're found. Crawling again with the same
+	// checkpoint skips the showings that have already been checked.
+	Checkpoint string
+}
+
+func (req *Request) logger() *slog.Logger {
+	if req.Logger == nil {
+		return slog.Default()
+	}
+	return req.Logger
+}
+
+func (req *Request) observe(ev Event) {
+	if req.Observer != nil {
+		req.Observer.Observe(ev)
+	}
+}
+
+// checkpointKey identifies the parts of req that decide which showings are
+// crawled and how they're judged.
+func (req *Request) checkpointKey() string {
+	var dates []string
+	for _, day := range req.Dates {
+		dates = append(dates, day.Format(time.DateOnly))
+	}
+	return fmt.Sprintf("zip=%s dates=%s titles=%q maxTheaters=%d seats=%d", req.Zip, strings.Join(dates, ","), req.titlesKey(), req.MaxTheaters, req.NumSeats)
 }
 
 // DurationRange is a range of allowable durations.
@@ -62,16 +155,87 @@ func (dr *DurationRange) Random() time.Duration {
 type Result struct {
 	Showings    []Showing
 	BadShowings []Showing
+	// Failed holds showings whose seats couldn't be checked.
+	Failed []FailedShowing
+	// Unreserved holds general admission showings, which have no seats to
+	// check.
+	Unreserved []Showing
 }
 
+// Add files sh under the part of res matching its verdict.
+func (res *Result) Add(sh Showing) {
+	switch sh.Verdict {
+	case VerdictGood:
+		res.Showings = append(res.Showings, sh)
+	case VerdictBad:
+		res.BadShowings = append(res.BadShowings, sh)
+	case VerdictUnreserved:
+		res.Unreserved = append(res.Unreserved, sh)
+	case VerdictFailed:
+		failed := FailedShowing{Showing: sh}
+		if len(sh.Attempts) > 0 {
+			last := sh.Attempts[len(sh.Attempts)-1]
+			failed.Err = last.Err
+			failed.Diagnostics = last.Diagnostics
+		}
+		res.Failed = append(res.Failed, failed)
+	}
+}
+
+// A FailedShowing is a showing whose seats couldn't be checked.
+type FailedShowing struct {
+	Showing
+	// Err is the error from the last attempt at crawling the showing's seats.
+	Err error
+	// Diagnostics is the diagnostics bundle from the last attempt, if any.
+	Diagnostics string
+}
+
+// A Verdict is the outcome of checking a showing's seats.
+type Verdict int
+
+const (
+	// VerdictUnknown means the showing's seats haven't been checked.
+	VerdictUnknown Verdict = iota
+	// VerdictGood means the showing has good seats available.
+	VerdictGood
+	// VerdictBad means the showing has no good seats available.
+	VerdictBad
+	// VerdictFailed means the showing's seats couldn't be checked.
+	VerdictFailed
+	// VerdictUnreserved means the showing is general admission, so there
+	// are no seats to check.
+	VerdictUnreserved
+)
+
 // A Showing is a single screening of a movie.
 type Showing struct {
-	Link    string
-	Theater string
-	When    time.Time
+	Link    string    `json:"link"`
+	Movie   string    `json:"movie"`
+	Theater string    `json:"theater"`
+	When    time.Time `json:"when"`
+	Verdict Verdict   `json:"verdict"`
 
-	// Not really part of the api -- consider splitting out.
-	Retries int
+	// Address is the theater's address, if it's listed.
+	Address string `json:"address,omitempty"`
+	// Distance is how far the theater is from Request.Zip in miles, or 0 if
+	// it isn't known.
+	Distance float64 `json:"distance,omitempty"`
+	// Runtime is the movie's runtime, or 0 if it isn't known.
+	Runtime time.Duration `json:"runtime,omitempty"`
+	// Format is how the movie is shown, e.g. FormatIMAX.
+	Format string `json:"format,omitempty"`
+	// Amenities are the theater's notes on the showing, e.g. "Reserved
+	// seating" or "Closed caption".
+	Amenities []string `json:"amenities,omitempty"`
+	// Favorite is whether the theater is one of Request.FavoriteTheaters.
+	Favorite bool `json:"favorite,omitempty"`
+	// Badge is the search page's hint about how many seats are left, e.g.
+	// "Selling fast" or "Sold out", if it gives one.
+	Badge string `json:"badge,omitempty"`
+
+	// Attempts is the history of tries at crawling the showing's seats.
+	Attempts []Attempt `json:"-"`
 }
 
 // Compare returns -1/0/1 depending on the relative ordering of sh and other.
@@ -82,100 +246,370 @@ func (sh *Showing) Compare(other Showing) int {
 	return sh.When.Compare(other.When)
 }
 
-// Crawl performs a full search based on req.
+// Crawl performs a full search based on req. See Client.Crawl.
 func Crawl(ctx context.Context, req Request) (Result, error) {
-	return crawlSearch(ctx, req, false /* skipCrawlSeats */)
+	client, err := NewClient(ClientOptions{Logger: req.Logger})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to start browser: %w", err)
+	}
+	defer closeClient(client)
+	return client.Crawl(ctx, req)
 }
 
 // CrawlSearch returns only the showing times and locations for req. It does not crawl seats.
 func CrawlSearch(ctx context.Context, req Request) (Result, error) {
-	return crawlSearch(ctx, req, true /* skipCrawlSeats */)
+	client, err := NewClient(ClientOptions{Logger: req.Logger})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to start browser: %w", err)
+	}
+	defer closeClient(client)
+	return client.CrawlSearch(ctx, req)
 }
 
-func crawlSearch(ctx context.Context, req Request, skipCrawlSeats bool) (Result, error) {
-	// Startup a browser.
-	browser, cleanup, err := startBrowser()
+// CrawlStream performs a full search based on req, yielding each showing as
+// soon as its seats are checked. See Client.CrawlStream.
+func CrawlStream(ctx context.Context, req Request) iter.Seq2[Showing, error] {
+	return func(yield func(Showing, error) bool) {
+		client, err := NewClient(ClientOptions{Logger: req.Logger})
+		if err != nil {
+			yield(Showing{}, fmt.Errorf("failed to start browser: %w", err))
+			return
+		}
+		defer closeClient(client)
+		for showing, err := range client.CrawlStream(ctx, req) {
+			if !yield(showing, err) {
+				return
+			}
+		}
+	}
+}
+
+// CrawlSeats returns whether the showing at link has good seats.
+//
+// TODO: Sometimes we get directed to a page where we choose between "classes"
+// of seats. We'll have to handle those.
+// TODO: Get smarter about determining seat location and what counts as good.
+func CrawlSeats(ctx context.Context, req Request, link string) (bool, error) {
+	client, err := NewClient(ClientOptions{Logger: req.Logger})
 	if err != nil {
-		return Result{}, fmt.Errorf("failed to start browser: %w", err)
+		return false, fmt.Errorf("failed to start browser: %w", err)
 	}
-	defer cleanup()
+	defer closeClient(client)
+	return client.CrawlSeats(ctx, req, link)
+}
 
-	stop := context.AfterFunc(ctx, func() { _ = browser.Close })
-	defer stop()
+func closeClient(client *Client) {
+	if err := client.Close(); err != nil {
+		client.logger.Info("failed to close client", "err", err)
+	}
+}
 
-	// Get the showings.
-	res, err := showings(req, browser)
+// Crawl performs a full search based on req. If the crawl stops early, e.g.
+// because ctx is done or the site blocked us, the showings checked so far are
+// returned along with the error.
+func (c *Client) Crawl(ctx context.Context, req Request) (Result, error) {
+	var res Result
+	for showing, err := range c.CrawlStream(ctx, req) {
+		if err != nil && showing.Verdict == VerdictUnknown {
+			return res, err
+		}
+		res.Add(showing)
+	}
+	return res, nil
+}
+
+// CrawlSearch returns only the showing times and locations for req. It does not crawl seats.
+// General admission showings are returned in Result.Unreserved.
+func (c *Client) CrawlSearch(ctx context.Context, req Request) (Result, error) {
+	c.crawlMu.Lock()
+	defer c.crawlMu.Unlock()
+	c.pacer.configure(req)
+
+	res, err := c.showings(ctx, req)
 	if err != nil {
 		return Result{}, fmt.Errorf("failed to get showings: %w", err)
 	}
-	slog.Debug("finished parsing showings", "numShowings", len(res.Showings))
-
-	if skipCrawlSeats {
-		return res, nil
+	var found Result
+	for _, showing := range filterShowings(req, res.Showings) {
+		if showing.Verdict == VerdictUnreserved {
+			found.Unreserved = append(found.Unreserved, showing)
+		} else {
+			found.Showings = append(found.Showings, showing)
+		}
 	}
+	return found, nil
+}
 
-	// Inspect the seating.
-	var (
-		good     []Showing
-		failures []Showing
-		nCrawled int
-	)
-	for ; nCrawled < len(res.Showings); nCrawled++ { // TODO: bad way to pass showings back up, inside the response struct
-		if uint(nCrawled) >= req.ShowingLimit {
-			break
+// CrawlStream performs a full search based on req, yielding each showing as
+// soon as its seats are checked. Showings whose seats couldn't be checked are
+// yielded with VerdictFailed and the last error. General admission showings,
+// which have no seats to check, are yielded first with VerdictUnreserved. If
+// the crawl can't continue, the zero Showing is yielded with the error and
+// iteration stops.
+//
+// The Client is free to use from the loop body, e.g. to start another crawl.
+// This crawl resumes once that one finishes.
+func (c *Client) CrawlStream(ctx context.Context, req Request) iter.Seq2[Showing, error] {
+	return func(yield func(Showing, error) bool) {
+		c.crawlMu.Lock()
+		defer c.crawlMu.Unlock()
+		c.pacer.configure(req)
+
+		// Let go of the Client while the caller handles each showing.
+		emit := yield
+		yield = func(showing Showing, err error) bool {
+			c.crawlMu.Unlock()
+			defer func() {
+				c.crawlMu.Lock()
+				c.pacer.configure(req)
+			}()
+			return emit(showing, err)
 		}
-		showing := &res.Showings[nCrawled]
-		ok, err := crawlSeats(req, browser, showing.Link)
+		logger := req.logger()
+
+		cp, err := loadCheckpoint(req.Checkpoint, req.checkpointKey())
 		if err != nil {
-			showing.Retries++
-			slog.Info("failed to check seats", " page", showing.Link, "retries", showing.Retries, "err", err)
-			if req.Retry && showing.Retries < retries {
-				nCrawled--
+			yield(Showing{}, err)
+			return
+		}
+
+		// Get the showings, either from where an earlier crawl left off or
+		// by searching.
+		var res Result
+		if cp.resumable() {
+			res.Showings = cp.Showings
+			logger.Info("resuming from checkpoint", "path", req.Checkpoint, "numShowings", len(res.Showings))
+		} else {
+			res, err = c.showings(ctx, req)
+			if err != nil {
+				yield(Showing{}, fmt.Errorf("failed to get showings: %w", err))
+				return
+			}
+			logger.Debug("finished parsing showings", "numShowings", len(res.Showings))
+			if err := cp.start(res.Showings); err != nil {
+				yield(Showing{}, err)
+				return
+			}
+		}
+
+		// Inspect the seating. Showings that fail are moved to the back of
+		// the queue so that their backoff doesn't hold up the others.
+		type queued struct {
+			showing   Showing
+			notBefore time.Time
+		}
+		var queue []queued
+		var unreserved []Showing
+		for _, showing := range sortShowings(req, filterShowings(req, res.Showings)) {
+			if showing.Verdict == VerdictUnreserved {
+				unreserved = append(unreserved, showing)
+				continue
+			}
+			// Keep going past the limit to collect the rest of the
+			// unreserved showings, which aren't checked anyway.
+			if uint(len(queue)) >= req.ShowingLimit {
+				continue
+			}
+			// Showings that failed last time get another chance.
+			if showing.Verdict == VerdictFailed {
+				showing.Verdict = VerdictUnknown
+			}
+			queue = append(queue, queued{showing: showing})
+			req.observe(ShowingQueuedEvent{Showing: showing})
+		}
+		for _, showing := range unreserved {
+			if !yield(showing, nil) {
+				return
+			}
+		}
+		var nGood, nBad, nFailed int
+		for len(queue) > 0 {
+			if req.StopAfter > 0 && uint(nGood) >= req.StopAfter {
+				logger.Info("found enough good showings", "goodShowings", nGood, "unchecked", len(queue))
+				break
+			}
+			item := queue[0]
+			queue = queue[1:]
+			showing := &item.showing
+
+			// Already checked by an earlier crawl.
+			if showing.Verdict == VerdictGood || showing.Verdict == VerdictBad {
+				if showing.Verdict == VerdictGood {
+					nGood++
+				} else {
+					nBad++
+				}
+				req.observe(SeatMapLoadedEvent{Showing: *showing})
+				if !yield(*showing, nil) {
+					return
+				}
+				continue
+			}
+
+			if err := ctx.Err(); err != nil {
+				yield(Showing{}, err)
+				return
+			}
+			if wait := time.Until(item.notBefore); wait > 0 {
+				logger.Debug("waiting to retry", "page", showing.Link, "wait", wait)
+				select {
+				case <-ctx.Done():
+					yield(Showing{}, ctx.Err())
+					return
+				case <-time.After(wait):
+				}
+			}
+
+			start := time.Now()
+			ok, bundle, err := c.crawlSeats(ctx, req, *showing)
+			showing.Attempts = append(showing.Attempts, Attempt{When: start, Err: err, Diagnostics: bundle})
+			if err != nil {
+				c.pacer.failed()
+				attempts := len(showing.Attempts)
+				logger.Info("failed to check seats", "page", showing.Link, "attempts", attempts, "err", err)
+				if req.Retry.shouldRetry(attempts, err) {
+					delay := req.Retry.delay(attempts)
+					item.notBefore = time.Now().Add(delay)
+					queue = append(queue, item)
+					req.observe(RetryEvent{Showing: *showing, Err: err, Delay: delay})
+					continue
+				}
+				if errors.Is(err, ErrBlocked) {
+					// Retrying, or moving on to the next showing, will
+					// only make it worse.
+					if bundle != "" {
+						err = fmt.Errorf("%w (diagnostics saved to %s)", err, bundle)
+					}
+					yield(Showing{}, fmt.Errorf("stopping crawl: %w", err))
+					return
+				}
+				nFailed++
+				showing.Verdict = VerdictFailed
+				if err := cp.record(*showing); err != nil {
+					logger.Info("failed to update checkpoint", "err", err)
+				}
+				req.observe(ShowingFailedEvent{Showing: *showing, Err: err})
+				if !yield(*showing, err) {
+					return
+				}
+				continue
+			}
+			c.pacer.succeeded()
+			if ok {
+				nGood++
+				showing.Verdict = VerdictGood
 			} else {
-				failures = append(failures, *showing)
+				nBad++
+				showing.Verdict = VerdictBad
+			}
+			if err := cp.record(*showing); err != nil {
+				logger.Info("failed to update checkpoint", "err", err)
+			}
+			req.observe(SeatMapLoadedEvent{Showing: *showing})
+			if !yield(*showing, nil) {
+				return
 			}
-			continue
 		}
-		if ok {
-			good = append(good, *showing)
-		} else {
-			res.BadShowings = append(res.BadShowings, *showing)
+		logger.Debug("seat crawlers finished", "goodShowings", nGood, "failedShowings", nFailed)
+		req.observe(DoneEvent{Good: nGood, Bad: nBad, Failed: nFailed})
+	}
+}
+
+// showings searches each of req.Dates for showings.
+func (c *Client) showings(ctx context.Context, req Request) (Result, error) {
+	if len(req.Dates) == 0 {
+		return Result{}, errors.New("no dates to search")
+	}
+	matcher, err := newTitleMatcher(req)
+	if err != nil {
+		return Result{}, err
+	}
+	var res Result
+	var noTheaters error
+	var searched int
+	for _, day := range req.Dates {
+		dayRes, err := c.showingsOn(ctx, req, day, matcher)
+		if errors.Is(err, ErrNoTheaters) {
+			// Other days may still have showings, e.g. when the
+			// range runs past what's been scheduled.
+			req.logger().Info("no theaters found", "date", day.Format(time.DateOnly), "err", err)
+			noTheaters = err
+			continue
+		} else if err != nil {
+			return Result{}, err
 		}
+		searched++
+		res.Showings = append(res.Showings, dayRes.Showings...)
 	}
-	slog.Debug("seat crawlers finished", "goodShowings", len(good))
-	fmt.Printf("Failed %d of %d requests (%f%% failures rate)\n", len(failures), nCrawled, float32(len(failures))/float32(nCrawled))
-	fmt.Printf("Failed to handle the following URLs. You may want to check them yourself (or even file a bug report!):\n")
-	for _, showing := range failures {
-		fmt.Printf("\t%s\n", showing.Link)
+	if searched == 0 && noTheaters != nil {
+		return Result{}, noTheaters
+	}
+	if err := matcher.checkMatches(req, res.Showings); err != nil {
+		return Result{}, err
 	}
-
-	res.Showings = good
 	return res, nil
 }
 
-func showings(req Request, browser playwright.Browser) (Result, error) {
-	browserCtx, err := browser.NewContext(playwright.BrowserNewContextOptions{UserAgent: playwright.String(userAgent)})
-	if err != nil {
-		return Result{}, fmt.Errorf("failed to create context: %w", err)
+// showingsOn searches day for showings.
+func (c *Client) showingsOn(ctx context.Context, req Request, day time.Time, matcher *titleMatcher) (res Result, err error) {
+	logger := req.logger()
+	searchURL := fmt.Sprintf("https://www.fandango.com/%s_movietimes?date=%s", req.Zip, day.Format("2006-01-02"))
+
+	// Results depend on the titles and how many theaters were listed as well
+	// as the page.
+	cacheKey := fmt.Sprintf("%s\nmaxTheaters=%d\n%s", searchURL, req.MaxTheaters, req.titlesKey())
+	if c.cache.get(cacheSearch, cacheKey, &res.Showings) {
+		logger.Debug("using cached search results", "URL", searchURL)
+		return res, nil
 	}
-	defer browserCtx.Close()
+	defer func() {
+		if err != nil {
+			return
+		}
+		if err := c.cache.put(cacheSearch, cacheKey, res.Showings); err != nil {
+			logger.Info("failed to cache search results", "URL", searchURL, "err", err)
+		}
+	}()
 
-	pg, err := browserCtx.NewPage()
+	browserCtx, pg, release, err := c.newPage(ctx)
 	if err != nil {
-		return Result{}, fmt.Errorf("failed to create page: %w", err)
+		return Result{}, err
 	}
-	defer pg.Close()
-	page := rateLimitedPage{Page: pg, interval: req.RequestInterval}
+	defer release()
+	page := rateLimitedPage{Page: pg, ctx: ctx, pacer: c.pacer, logger: logger}
 
 	// Navigate to the search page and get a list of theaters.
-	searchURL := fmt.Sprintf("https://www.fandango.com/%s_movietimes?date=%s", req.Zip, req.Date.Format("2006-01-02"))
-	slog.Debug("searching", "URL", searchURL)
+
+	diag := startDiagnostics(req, browserCtx, pg)
+	defer func() {
+		if err == nil {
+			diag.discard()
+			return
+		}
+		bundle, saveErr := diag.save(fmt.Sprintf("search %s %s", req.Zip, day.Format("2006-01-02")), err)
+		if saveErr != nil {
+			logger.Info("failed to save diagnostics", "URL", searchURL, "err", saveErr)
+		}
+		if bundle != "" {
+			err = fmt.Errorf("%w (diagnostics saved to %s)", err, bundle)
+		}
+	}()
+
+	logger.Debug("searching", "URL", searchURL)
+	req.observe(SearchStartedEvent{URL: searchURL})
 	if _, err := page.Goto(searchURL); err != nil {
 		return Result{}, fmt.Errorf("failed to load page at %q: %w", searchURL, err)
 	}
-	theaters, err := page.Locator(".fd-showtimes .fd-theater").All()
-	if err != nil || len(theaters) == 0 {
+	theaters, err := expandTheaters(&page, req.MaxTheaters)
+	if err != nil {
 		return Result{}, fmt.Errorf("failed to find theaters on page %q: %w", searchURL, err)
+	} else if len(theaters) == 0 {
+		// Maybe there are no theaters, or maybe the site is just
+		// refusing to show them to us.
+		if err := checkBlocked(page.Page); err != nil {
+			return Result{}, err
+		}
+		return Result{}, fmt.Errorf("%w on page %q", ErrNoTheaters, searchURL)
 	}
 
 	// From here on out, errors aren't fatal. That is: we can fail with one
@@ -185,7 +619,6 @@ func showings(req Request, browser playwright.Browser) (Result, error) {
 	// slog-friendly {k, v, k, v, ...} context for errors.
 	errCtx := []any{"searchPage", searchURL}
 
-	var res Result
 	for _, theater := range theaters {
 		// Every iteration gets its own shadow of errCtx. We add elements as we
 		// go, and those elements propogate down the call stack. But the next
@@ -195,22 +628,24 @@ func showings(req Request, browser playwright.Browser) (Result, error) {
 		// Get the name of the theater.
 		theaterNameNodes, err := theater.Locator(".fd-theater__name > a").All()
 		if err != nil || len(theaterNameNodes) == 0 {
-			info("failed to find theater name nodes", errCtx, "err", err, "ntheaternodes", len(theaterNameNodes))
+			info(logger, "failed to find theater name nodes", errCtx, "err", err, "ntheaternodes", len(theaterNameNodes))
 			continue
 		}
 		theaterName, err := theaterNameNodes[0].TextContent()
 		if err != nil {
-			info("failed to get text content of theater name node", errCtx, "err", err)
+			info(logger, "failed to get text content of theater name node", errCtx, "err", err)
 			continue
 		}
 		theaterName = strings.TrimSpace(theaterName)
 		errCtx = append(errCtx, "theater", theaterName)
-		slog.Debug("handling theater", "theaterName", theaterName)
+		address, distance := theaterLocation(theater)
+		logger.Debug("handling theater", "theaterName", theaterName, "address", address, "distance", distance)
+		nShowings := len(res.Showings)
 
 		// Iterate over the movies at this theater.
 		movieNodes, err := theater.Locator(".fd-movie").All()
 		if err != nil || len(movieNodes) == 0 {
-			info("failed to find a movie node on page", errCtx, "err", err, "nmovienodes", len(movieNodes))
+			info(logger, "failed to find a movie node on page", errCtx, "err", err, "nmovienodes", len(movieNodes))
 			continue
 		}
 
@@ -224,112 +659,154 @@ func showings(req Request, browser playwright.Browser) (Result, error) {
 			titleOrNoShowtimeNode := noShowtimeLocator.Or(titleLocator).First()
 			noShowtimes, err := noShowtimeLocator.IsVisible()
 			if err != nil {
-				info("failed to check visiblity of no showtime locator", errCtx, "err", err)
+				info(logger, "failed to check visiblity of no showtime locator", errCtx, "err", err)
 				continue
 			}
 			if noShowtimes {
-				slog.Debug("no showings available", errCtx...)
+				logger.Debug("no showings available", errCtx...)
 				continue
 			}
 
 			titleNode := titleOrNoShowtimeNode
 			if titleNode == nil { // TODO: Some of these len checks can be removed, and we can just First() instead of all.
-				info("failed to find a movie title for", errCtx, "err", err)
+				info(logger, "failed to find a movie title for", errCtx, "err", err)
 				continue
 			}
 			var timeoutMS float64 = 30_000 // TODO: Find a better way to check for this.
 			title, err := titleNode.TextContent(playwright.LocatorTextContentOptions{Timeout: &timeoutMS})
 			if err != nil {
-				info("failed to get text content of title node", errCtx, "err", err)
+				info(logger, "failed to get text content of title node", errCtx, "err", err)
 				continue
 			}
-			if !strings.Contains(strings.ToLower(title), strings.ToLower(req.Title)) {
+			title = strings.TrimSpace(title)
+			if _, ok := matcher.match(title); !ok {
 				continue
 			}
-			slog.Debug("found matching movie", "title", title)
+			logger.Debug("found matching movie", "title", title)
 			errCtx = append(errCtx, "title", title)
 
+			// The runtime is nice to have, but not worth waiting for.
+			var runtime time.Duration
+			if nodes, err := movieNode.Locator(".fd-movie__rating-runtime").All(); err == nil && len(nodes) > 0 {
+				if text, err := nodes[0].TextContent(); err == nil {
+					runtime = parseRuntime(text)
+				}
+			}
+			if runtime == 0 {
+				logger.Debug("couldn't find runtime", errCtx...)
+			}
+
 			// Find variants with reserved seating.
 			variants, err := movieNode.Locator("li.fd-movie__showtimes-variant").All()
 			if err != nil || len(variants) == 0 {
-				info("failed when finding variants", errCtx, "nvariants", len(variants))
+				info(logger, "failed when finding variants", errCtx, "nvariants", len(variants))
 				continue
 			}
 
 			for i, variant := range variants {
 				errCtx := errCtx
 
-				slog.Debug("checking variant", "variant", i)
-				// Only get showtimes with reserved seating.
-				amenities, err := variant.Locator(".fd-movie__amenity-list > li > button").All()
+				logger.Debug("checking variant", "variant", i)
+				amenityNodes, err := variant.Locator(".fd-movie__amenity-list > li > button").All()
 				if err != nil {
-					info("failed to get amenities list", errCtx, "err", err)
+					info(logger, "failed to get amenities list", errCtx, "err", err)
 					continue
 				}
+				var amenities []string
 				var reserved bool
-				for _, amenity := range amenities {
+				for _, amenity := range amenityNodes {
 					text, err := amenity.TextContent()
 					if err != nil {
-						info("failed to get text content for amenity", errCtx, "err", err)
+						info(logger, "failed to get text content for amenity", errCtx, "err", err)
 						continue
 					}
+					text = strings.TrimSpace(text)
+					if text == "" {
+						continue
+					}
+					amenities = append(amenities, text)
 					if strings.Contains(strings.ToLower(text), "reserve") {
 						reserved = true
-						slog.Debug("found reserved seating", "amenity", text)
-						break
+						logger.Debug("found reserved seating", "amenity", text)
 					}
 				}
+				// Showtimes without reserved seating have no seat map
+				// to check, but they're still worth knowing about.
+				verdict := VerdictUnknown
 				if !reserved {
-					continue
+					verdict = VerdictUnreserved
 				}
 
+				// The format is named in the variant's heading, and
+				// sometimes only in its amenities.
+				formatTexts := slices.Clone(amenities)
+				if headings, err := variant.Locator("h3, h4").All(); err == nil {
+					for _, heading := range headings {
+						if text, err := heading.TextContent(); err == nil {
+							formatTexts = append(formatTexts, strings.TrimSpace(text))
+						}
+					}
+				}
+				format := parseFormat(formatTexts)
+				errCtx = append(errCtx, "format", format)
+
 				// Get showings.
 				showings, err := variant.Locator("li.showtimes-btn-list__item > a").All()
 				if err != nil || len(showings) == 0 {
-					info("failed to get showings list", errCtx, "err", err, "nshowings", len(showings))
+					info(logger, "failed to get showings list", errCtx, "err", err, "nshowings", len(showings))
 					continue
 				}
-				slog.Debug("found showings", "nshowings", len(showings))
+				logger.Debug("found showings", "nshowings", len(showings))
 				for _, showing := range showings {
 					errCtx := errCtx
 
 					text, err := showing.TextContent()
 					if err != nil {
-						info("failed to get text content for showing", errCtx, "err", err)
+						info(logger, "failed to get text content for showing", errCtx, "err", err)
 						continue
 					}
-					slog.Debug("found showing", "time", text)
+					logger.Debug("found showing", "time", text)
 
-					// The text is a bunch of whitespace
-					// surrounding a string like "9:30a" or
-					// "12:30p".
-					showtime, err := time.Parse("3:04pm", strings.TrimSpace(text)+"m")
+					showtime, err := parseShowtime(text)
 					if err != nil {
-						info("failed to parse time", errCtx, "err", err, "time", text)
+						info(logger, "failed to parse time", errCtx, "err", err, "time", text)
 						continue
 					}
-					showtime = showtime.AddDate(
-						req.Date.Year(),
-						int(req.Date.Month()),
-						req.Date.Day(),
-					)
+					showtime = time.Date(day.Year(), day.Month(), day.Day(), showtime.Hour(), showtime.Minute(), 0, 0, day.Location())
 					errCtx = append(errCtx, "showtime", showtime)
 
 					link, err := showing.GetAttribute("href")
 					if err != nil {
-						info("failed to get link", errCtx, "err", err)
+						info(logger, "failed to get link", errCtx, "err", err)
 						continue
 					}
 					errCtx = append(errCtx, "seatsLink", link)
 
+					// Badges may be outside the link itself.
+					badge := parseBadge(text)
+					if badge == "" {
+						if itemText, err := showing.Locator("xpath=..").TextContent(); err == nil {
+							badge = parseBadge(itemText)
+						}
+					}
+
 					res.Showings = append(res.Showings, Showing{
-						Link:    link,
-						Theater: theaterName,
-						When:    showtime,
+						Link:      link,
+						Movie:     title,
+						Theater:   theaterName,
+						Address:   address,
+						Distance:  distance,
+						When:      showtime,
+						Runtime:   runtime,
+						Format:    format,
+						Amenities: amenities,
+						Badge:     badge,
+						Verdict:   verdict,
 					})
 				}
 			}
 		}
+		req.observe(TheaterParsedEvent{Theater: theaterName, NumShowings: len(res.Showings) - nShowings})
 	}
 
 	return res, nil
@@ -341,41 +818,128 @@ type seat struct {
 	reserved bool
 }
 
-// TODO: Sometimes we get directed to a page where we choose between "classes"
-// of seats. We'll have to handle those.
-// TODO: Get smarter about determining seat location and what counts as good.
-func CrawlSeats(ctx context.Context, req Request, link string) (bool, error) {
-	browser, cleanup, err := startBrowser()
+// CrawlSeats returns whether the showing at link has good seats.
+func (c *Client) CrawlSeats(ctx context.Context, req Request, link string) (bool, error) {
+	c.crawlMu.Lock()
+	defer c.crawlMu.Unlock()
+	c.pacer.configure(req)
+
+	good, bundle, err := c.crawlSeats(ctx, req, Showing{Link: link})
+	if bundle != "" {
+		req.logger().Info("saved diagnostics", "URL", link, "bundle", bundle)
+	}
+	return good, err
+}
+
+// crawlSeats returns whether showing has good seats. If it fails and req asks
+// for diagnostics, it also returns the diagnostics bundle.
+func (c *Client) crawlSeats(ctx context.Context, req Request, showing Showing) (good bool, bundle string, err error) {
+	seats, bundle, err := c.seatMap(ctx, req, showing)
 	if err != nil {
-		return false, fmt.Errorf("failed to start browser: %w", err)
+		return false, bundle, err
 	}
-	defer cleanup()
+	if req.SnapshotDir != "" {
+		if _, err := SaveSnapshot(req.SnapshotDir, newSnapshot(showing, seats, time.Now())); err != nil {
+			req.logger().Info("failed to save snapshot", "URL", showing.Link, "err", err)
+		}
+	}
+	good = checkSeats(seats.seats, seats.maxRow, seats.maxCol, req.NumSeats)
+	req.logger().Debug("checked seats", "URL", showing.Link, "good", good)
+	return good, "", nil
+}
 
-	stop := context.AfterFunc(ctx, func() { _ = browser.Close })
-	defer stop()
+// A seatMap holds the seats in a showing's auditorium.
+type seatMap struct {
+	seats  []seat
+	maxRow int
+	maxCol int
+}
 
-	// This is a one-off. Ignore the interval.
-	return crawlSeats(req, browser, link)
+// A seatLayout is where the seats in a seatMap are, which is cached
+// separately from which seats are reserved. Both are in seat order.
+type seatLayout struct {
+	Rows []int `json:"rows"`
+	Cols []int `json:"cols"`
 }
 
-func crawlSeats(req Request, browser playwright.Browser, link string) (bool, error) {
-	slog.Debug("crawling seats", "URL", link)
-	// Navigate to the search page and get a list of theaters.
-	browserCtx, err := browser.NewContext(playwright.BrowserNewContextOptions{UserAgent: playwright.String(userAgent)})
-	if err != nil {
-		return false, fmt.Errorf("failed to create context: %w", err)
+type seatAvailability struct {
+	Reserved []bool `json:"reserved"`
+}
+
+func (sm *seatMap) layout() seatLayout {
+	var layout seatLayout
+	for _, st := range sm.seats {
+		layout.Rows = append(layout.Rows, st.row)
+		layout.Cols = append(layout.Cols, st.col)
 	}
-	defer browserCtx.Close()
+	return layout
+}
 
-	pg, err := browserCtx.NewPage()
+func (sm *seatMap) availability() seatAvailability {
+	var availability seatAvailability
+	for _, st := range sm.seats {
+		availability.Reserved = append(availability.Reserved, st.reserved)
+	}
+	return availability
+}
+
+// newSeatMap puts a layout and availability back together. It returns false if
+// they don't describe the same seats.
+func newSeatMap(layout seatLayout, availability seatAvailability) (seatMap, bool) {
+	if len(layout.Rows) == 0 || len(layout.Rows) != len(layout.Cols) || len(layout.Rows) != len(availability.Reserved) {
+		return seatMap{}, false
+	}
+	var sm seatMap
+	for i := range layout.Rows {
+		sm.seats = append(sm.seats, seat{row: layout.Rows[i], col: layout.Cols[i], reserved: availability.Reserved[i]})
+		sm.maxRow = max(sm.maxRow, layout.Rows[i])
+		sm.maxCol = max(sm.maxCol, layout.Cols[i])
+	}
+	return sm, true
+}
+
+// seatMap returns showing's seat map, from the cache if it's fresh enough. If
+// it fails and req asks for diagnostics, it also returns the diagnostics
+// bundle.
+func (c *Client) seatMap(ctx context.Context, req Request, showing Showing) (seats seatMap, bundle string, err error) {
+	logger := req.logger()
+	link := showing.Link
+
+	var (
+		layout       seatLayout
+		availability seatAvailability
+	)
+	haveLayout := c.cache.get(cacheLayout, link, &layout)
+	if haveLayout && c.cache.get(cacheAvailability, link, &availability) {
+		if sm, ok := newSeatMap(layout, availability); ok {
+			logger.Debug("using cached seats", "URL", link)
+			return sm, "", nil
+		}
+	}
+
+	logger.Debug("crawling seats", "URL", link)
+	browserCtx, pg, release, err := c.newPage(ctx)
 	if err != nil {
-		return false, fmt.Errorf("failed to create seat page: %w", err)
+		return seatMap{}, "", err
 	}
-	defer pg.Close()
-	page := rateLimitedPage{Page: pg, interval: req.RequestInterval}
+	defer release()
+	page := rateLimitedPage{Page: pg, ctx: ctx, pacer: c.pacer, logger: logger}
+
+	diag := startDiagnostics(req, browserCtx, pg)
+	defer func() {
+		if err == nil {
+			diag.discard()
+			return
+		}
+		var saveErr error
+		bundle, saveErr = diag.save(fmt.Sprintf("%s %s", showing.Theater, showing.When.Format("2006-01-02 1504")), err)
+		if saveErr != nil {
+			logger.Info("failed to save diagnostics", "URL", link, "err", saveErr)
+		}
+	}()
 
 	if _, err := page.Goto(link); err != nil {
-		return false, fmt.Errorf("failed to load page at %q: %w", link, err)
+		return seatMap{}, "", fmt.Errorf("failed to load page at %q: %w", link, err)
 	}
 
 	// We have to parse the seating chart. We make the following
@@ -392,29 +956,75 @@ func crawlSeats(req Request, browser playwright.Browser, link string) (bool, err
 	// end. But it should be good enough for now.
 
 	// TODO: Play with this timeout.
-	var seatMapTimeoutMS float64 = 30_000
-	if err := page.Locator(".seat-map__seat").First().WaitFor(playwright.LocatorWaitForOptions{Timeout: &seatMapTimeoutMS}); err != nil {
-		return false, fmt.Errorf("failed to wait for seats on page: %v", err)
+	if err := waitForSeats(page.Page, link, 30*time.Second); err != nil {
+		return seatMap{}, "", err
 	}
 
-	seatDivs, err := page.Locator(".seat-map__seat:not(.wheelchair):not(.companion)").All()
-	if err != nil {
-		return false, fmt.Errorf("failed to find seats: %w", err)
-	} else if len(seatDivs) == 0 {
-		str, err := page.Content()
+	seatLocator := page.Locator(seatSelector)
+	if haveLayout {
+		// Only availability is stale, and reading it is far cheaper than
+		// reading the layout.
+		availability, err := readAvailability(seatLocator, link)
 		if err != nil {
-			panic(err)
+			return seatMap{}, "", err
 		}
-		tmp, err := os.CreateTemp("", "seating-")
-		if err != nil {
-			panic(err)
+		if sm, ok := newSeatMap(layout, availability); ok {
+			c.cacheSeatMap(logger, link, sm, false /* withLayout */)
+			return sm, "", nil
 		}
-		if _, err := fmt.Fprint(tmp, str); err != nil {
-			panic(err)
+		logger.Debug("cached seat layout is out of date", "URL", link)
+	}
+
+	sm, err := readSeatMap(seatLocator, link)
+	if err != nil {
+		return seatMap{}, "", err
+	}
+	c.cacheSeatMap(logger, link, sm, true /* withLayout */)
+	return sm, "", nil
+}
+
+func (c *Client) cacheSeatMap(logger *slog.Logger, link string, sm seatMap, withLayout bool) {
+	if withLayout {
+		if err := c.cache.put(cacheLayout, link, sm.layout()); err != nil {
+			logger.Info("failed to cache seat layout", "URL", link, "err", err)
+		}
+	}
+	if err := c.cache.put(cacheAvailability, link, sm.availability()); err != nil {
+		logger.Info("failed to cache seat availability", "URL", link, "err", err)
+	}
+}
+
+// readAvailability reads which of the seats selected by seatLocator are
+// reserved.
+func readAvailability(seatLocator playwright.Locator, link string) (seatAvailability, error) {
+	handle, err := seatLocator.EvaluateAll("elements => elements.map(element => element.getAttribute('aria-disabled'))")
+	if err != nil {
+		return seatAvailability{}, fmt.Errorf("failed to get reservation statuses: %w", err)
+	}
+	statuses, ok := handle.([]any)
+	if !ok {
+		return seatAvailability{}, &ParseError{URL: link, Selector: seatSelector, Err: fmt.Errorf("reservation statuses %v are not a list", handle)}
+	}
+	var availability seatAvailability
+	for _, status := range statuses {
+		disabled, _ := status.(string)
+		reserved, err := parseReserved(disabled, link)
+		if err != nil {
+			return seatAvailability{}, err
 		}
-		slog.Info("no seats found", "URL", page.URL(), "pageDump", tmp.Name())
+		availability.Reserved = append(availability.Reserved, reserved)
+	}
+	return availability, nil
+}
 
-		return false, fmt.Errorf("no seats found with link: %q", link)
+// readSeatMap reads the layout and availability of the seats selected by
+// seatLocator.
+func readSeatMap(seatLocator playwright.Locator, link string) (seatMap, error) {
+	seatDivs, err := seatLocator.All()
+	if err != nil {
+		return seatMap{}, fmt.Errorf("failed to find seats: %w", err)
+	} else if len(seatDivs) == 0 {
+		return seatMap{}, fmt.Errorf("%w with link: %q", ErrNoSeats, link)
 	}
 
 	// Currently, building the seat map and checking for good seats
@@ -443,9 +1053,12 @@ func crawlSeats(req Request, browser playwright.Browser, link string) (bool, err
 			nil,
 		)
 		if err != nil {
-			return false, fmt.Errorf("failed to get seat element top: %w", err)
+			return seatMap{}, fmt.Errorf("failed to get seat element top: %w", err)
+		}
+		top, ok := handle.(string)
+		if !ok {
+			return seatMap{}, &ParseError{URL: link, Selector: seatSelector, Err: fmt.Errorf("seat top %v is not a string", handle)}
 		}
-		top := handle.(string)
 		if top != curTop {
 			curTop = top
 			row++
@@ -454,15 +1067,11 @@ func crawlSeats(req Request, browser playwright.Browser, link string) (bool, err
 
 		disabled, err := seatDiv.GetAttribute("aria-disabled")
 		if err != nil {
-			return false, fmt.Errorf("failed to get reservation status: %w", err)
+			return seatMap{}, fmt.Errorf("failed to get reservation status: %w", err)
 		}
-		var reserved bool
-		switch disabled {
-		case "true":
-			reserved = true
-		case "false":
-		default:
-			return false, fmt.Errorf("failed to parse aria-disabled attribute %q", disabled)
+		reserved, err := parseReserved(disabled, link)
+		if err != nil {
+			return seatMap{}, err
 		}
 		seats = append(seats, seat{row: row, col: col, reserved: reserved})
 
@@ -470,9 +1079,19 @@ func crawlSeats(req Request, browser playwright.Browser, link string) (bool, err
 		col++
 	}
 
-	good := checkSeats(seats, row, maxCol, req.NumSeats)
-	slog.Debug("crawled seats", "URL", link, "good", true)
-	return good, nil
+	return seatMap{seats: seats, maxRow: row, maxCol: maxCol}, nil
+}
+
+// parseReserved parses a seat's aria-disabled attribute.
+func parseReserved(disabled, link string) (bool, error) {
+	switch disabled {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	default:
+		return false, &ParseError{URL: link, Selector: seatSelector, Err: fmt.Errorf("unknown aria-disabled attribute %q", disabled)}
+	}
 }
 
 func checkSeats(seats []seat, maxRow, maxCol, numSeats int) bool {
@@ -508,32 +1127,6 @@ func checkSeats(seats []seat, maxRow, maxCol, numSeats int) bool {
 	return false
 }
 
-// startBrowser returns a Browser, cleanup method, and error.
-func startBrowser() (playwright.Browser, func(), error) {
-	// Boot up playwright.
-	opts := &playwright.RunOptions{SkipInstallBrowsers: true}
-	if err := playwright.Install(opts); err != nil {
-		return nil, nil, fmt.Errorf("failed to install playwright drivers: %w", err)
-	}
-	pw, err := playwright.Run()
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to run playwright: %w", err)
-	}
-	browser, err := pw.Chromium.Launch()
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to launch browser (do you have NPM and NPX installed?): %w, pw.Stop(): %v", err, pw.Stop())
-	}
-	cleanup := func() {
-		if err := browser.Close(); err != nil {
-			slog.Info("failed to stop browser", "err", err)
-		}
-		if err := pw.Stop(); err != nil {
-			slog.Info("failed to stop playwright", "err", err)
-		}
-	}
-	return browser, cleanup, nil
-}
-
-func info(msg string, errCtx []any, args ...any) {
-	slog.Info(msg, append(errCtx, args)...)
+func info(logger *slog.Logger, msg string, errCtx []any, args ...any) {
+	logger.Info(msg, append(errCtx, args...)...)
 }
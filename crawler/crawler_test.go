@@ -1,7 +1,9 @@
 package crawler
 
 import (
+	"context"
 	"testing"
+	"time"
 )
 
 // This really isn't thorough enough.
@@ -160,3 +162,21 @@ func TestGoodSeats(t *testing.T) {
 		})
 	}
 }
+
+func TestShowingsNoDates(t *testing.T) {
+	// Without dates there's nothing to search, so the Client is never used.
+	var client Client
+	if _, err := client.showings(context.Background(), Request{Zip: "48104"}); err == nil {
+		t.Errorf("showings() with no dates succeeded, want error")
+	}
+}
+
+// clockTime returns the time of day clocktime, e.g. "19:30", on the zero day.
+func clockTime(t *testing.T, clocktime string) time.Time {
+	t.Helper()
+	tim, err := time.Parse("15:04", clocktime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tim
+}
@@ -0,0 +1,128 @@
+package crawler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+
+	playwright "github.com/playwright-community/playwright-go"
+)
+
+// diagnostics collects evidence about a page as it's crawled so that, if the
+// page fails, it can be saved as a bundle for bug reports. A nil diagnostics
+// collects nothing.
+type diagnostics struct {
+	dir        string
+	trace      bool
+	browserCtx playwright.BrowserContext
+	page       playwright.Page
+
+	mu      sync.Mutex
+	console []string
+}
+
+// startDiagnostics starts collecting diagnostics about page, or returns nil if
+// req doesn't ask for diagnostics.
+func startDiagnostics(req Request, browserCtx playwright.BrowserContext, page playwright.Page) *diagnostics {
+	if req.DiagnosticsDir == "" {
+		return nil
+	}
+	dg := &diagnostics{
+		dir:        req.DiagnosticsDir,
+		trace:      req.Trace,
+		browserCtx: browserCtx,
+		page:       page,
+	}
+	page.OnConsole(func(msg playwright.ConsoleMessage) {
+		dg.mu.Lock()
+		defer dg.mu.Unlock()
+		dg.console = append(dg.console, fmt.Sprintf("[%s] %s", msg.Type(), msg.Text()))
+	})
+	if dg.trace {
+		opts := playwright.TracingStartOptions{
+			Screenshots: playwright.Bool(true),
+			Snapshots:   playwright.Bool(true),
+		}
+		if err := browserCtx.Tracing().Start(opts); err != nil {
+			req.logger().Info("failed to start trace", "err", err)
+			dg.trace = false
+		}
+	}
+	return dg
+}
+
+// save writes a bundle describing the page and the error it failed with to a
+// new directory named after name. It returns the directory, which may hold a
+// partial bundle even if there's an error.
+func (dg *diagnostics) save(name string, pageErr error) (string, error) {
+	if dg == nil {
+		return "", nil
+	}
+	if err := os.MkdirAll(dg.dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create diagnostics directory: %w", err)
+	}
+	dir, err := os.MkdirTemp(dg.dir, bundleName(name)+"-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create diagnostics bundle: %w", err)
+	}
+
+	var errs []error
+	summary := fmt.Sprintf("URL: %s\nError: %v\n", dg.page.URL(), pageErr)
+	if err := os.WriteFile(filepath.Join(dir, "error.txt"), []byte(summary), 0o644); err != nil {
+		errs = append(errs, fmt.Errorf("failed to write error: %w", err))
+	}
+	screenshotOpts := playwright.PageScreenshotOptions{
+		FullPage: playwright.Bool(true),
+		Path:     playwright.String(filepath.Join(dir, "screenshot.png")),
+	}
+	if _, err := dg.page.Screenshot(screenshotOpts); err != nil {
+		errs = append(errs, fmt.Errorf("failed to take screenshot: %w", err))
+	}
+	if content, err := dg.page.Content(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to get page content: %w", err))
+	} else if err := os.WriteFile(filepath.Join(dir, "page.html"), []byte(content), 0o644); err != nil {
+		errs = append(errs, fmt.Errorf("failed to write page content: %w", err))
+	}
+	dg.mu.Lock()
+	console := strings.Join(dg.console, "\n")
+	dg.mu.Unlock()
+	if err := os.WriteFile(filepath.Join(dir, "console.log"), []byte(console), 0o644); err != nil {
+		errs = append(errs, fmt.Errorf("failed to write console log: %w", err))
+	}
+	if dg.trace {
+		dg.trace = false
+		if err := dg.browserCtx.Tracing().Stop(filepath.Join(dir, "trace.zip")); err != nil {
+			errs = append(errs, fmt.Errorf("failed to save trace: %w", err))
+		}
+	}
+	return dir, errors.Join(errs...)
+}
+
+// discard throws away anything collected, e.g. because the page succeeded.
+func (dg *diagnostics) discard() {
+	if dg == nil || !dg.trace {
+		return
+	}
+	dg.trace = false
+	_ = dg.browserCtx.Tracing().Stop()
+}
+
+// bundleName turns name into something safe and readable as a file name.
+func bundleName(name string) string {
+	var builder strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+			dash = false
+		} else if !dash && builder.Len() > 0 {
+			builder.WriteRune('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(builder.String(), "-")
+}
@@ -0,0 +1,22 @@
+package crawler
+
+import (
+	"testing"
+)
+
+func TestBundleName(t *testing.T) {
+	tcs := []struct {
+		input string
+		want  string
+	}{
+		{input: "AMC Ann Arbor 20 2026-10-18 1930", want: "amc-ann-arbor-20-2026-10-18-1930"},
+		{input: "  Regal: Fenton & IMAX/4DX  ", want: "regal-fenton-imax-4dx"},
+		{input: "../../etc/passwd", want: "etc-passwd"},
+		{input: "Cinéma Café", want: "cinéma-café"},
+	}
+	for _, tc := range tcs {
+		if got := bundleName(tc.input); got != tc.want {
+			t.Errorf("bundleName(%q) = %q, want %q", tc.input, got, tc.want)
+		}
+	}
+}
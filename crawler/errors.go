@@ -0,0 +1,55 @@
+package crawler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNoTheaters means the search page didn't list any theaters.
+	ErrNoTheaters = errors.New("no theaters found")
+	// ErrSeatMapTimeout means a seat map didn't render in time. Errors
+	// wrapping it also wrap playwright.ErrTimeout.
+	ErrSeatMapTimeout = errors.New("timed out waiting for seat map")
+	// ErrNoSeats means a seat map rendered but had no seats in it.
+	ErrNoSeats = errors.New("no seats found")
+	// ErrBlocked means the site refused to serve a page, likely because it
+	// thinks we're a bot.
+	ErrBlocked = errors.New("blocked by site")
+
+	// errRateLimited means the site asked us to slow down. Only an adaptive
+	// pacer, which widens its interval in response, returns it, so it's
+	// worth retrying.
+	errRateLimited = errors.New("rate limited by site")
+)
+
+// A ParseError means part of a page couldn't be understood.
+type ParseError struct {
+	// URL is the page being parsed.
+	URL string
+	// Selector selects the element that couldn't be parsed.
+	Selector string
+	// Err describes what went wrong.
+	Err error
+}
+
+func (pe *ParseError) Error() string {
+	return fmt.Sprintf("failed to parse %q on page %q: %v", pe.Selector, pe.URL, pe.Err)
+}
+
+func (pe *ParseError) Unwrap() error {
+	return pe.Err
+}
+
+// An AmbiguousTitleError means a title matched several different movies.
+type AmbiguousTitleError struct {
+	// Title is the title searched for.
+	Title string
+	// Movies are the movies it matched.
+	Movies []string
+}
+
+func (ae *AmbiguousTitleError) Error() string {
+	return fmt.Sprintf("title %q matches %d movies: %s", ae.Title, len(ae.Movies), strings.Join(ae.Movies, "; "))
+}
@@ -0,0 +1,99 @@
+package crawler
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// filterShowings returns the showings that req's filters allow, with
+// Showing.Favorite set. Filtering happens before any seats are checked, so it's
+// much cheaper than crawling showings nobody can attend.
+func filterShowings(req Request, showings []Showing) []Showing {
+	logger := req.logger()
+	var kept []Showing
+	for _, showing := range showings {
+		if reason := req.exclude(showing); reason != "" {
+			logger.Debug("skipping showing", "page", showing.Link, "reason", reason)
+			continue
+		}
+		showing.Favorite = matchTheater(showing.Theater, req.FavoriteTheaters)
+		kept = append(kept, showing)
+	}
+	if skipped := len(showings) - len(kept); skipped > 0 {
+		logger.Info("filtered out showings", "skipped", skipped, "kept", len(kept))
+	}
+	return kept
+}
+
+// exclude returns why req's filters exclude showing, or "" if they don't.
+func (req *Request) exclude(showing Showing) string {
+	if len(req.Theaters) > 0 && !matchTheater(showing.Theater, req.Theaters) {
+		return fmt.Sprintf("theater %q isn't wanted", showing.Theater)
+	}
+	if matchTheater(showing.Theater, req.ExcludeTheaters) {
+		return fmt.Sprintf("theater %q is excluded", showing.Theater)
+	}
+	if req.Radius != 0 && showing.Distance > req.Radius {
+		return fmt.Sprintf("theater is %.1f miles away", showing.Distance)
+	}
+	start := clock(showing.When)
+	if start < req.After {
+		return fmt.Sprintf("starts before %s", formatClock(req.After))
+	}
+	if req.Before != 0 && start > req.Before {
+		return fmt.Sprintf("starts after %s", formatClock(req.Before))
+	}
+	if len(req.Formats) > 0 && !matchFormat(showing.Format, req.Formats) {
+		return fmt.Sprintf("format %q isn't wanted", showing.Format)
+	}
+	if matchFormat(showing.Format, req.ExcludeFormats) {
+		return fmt.Sprintf("format %q is excluded", showing.Format)
+	}
+	// Without a runtime there's no telling when it ends, so keep it.
+	if req.EndsBefore != 0 && showing.Runtime != 0 && start+showing.Runtime > req.EndsBefore {
+		return fmt.Sprintf("ends after %s", formatClock(req.EndsBefore))
+	}
+	return ""
+}
+
+// matchTheater returns whether theater's name contains any of patterns,
+// ignoring case and punctuation.
+func matchTheater(theater string, patterns []string) bool {
+	normalized := normalizeTitle(theater)
+	for _, pattern := range patterns {
+		if want := normalizeTitle(pattern); want != "" && strings.Contains(normalized, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatClock formats a time since midnight, e.g. "18:30".
+func formatClock(clock time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(clock.Hours()), int(clock.Minutes())%60)
+}
+
+// runtimeRegex matches runtimes like "2 hr 7 min", "1 hr" or "95 min".
+var runtimeRegex = regexp.MustCompile(`(?i)(?:(\d+)\s*hrs?)?\s*(?:(\d+)\s*mins?)?`)
+
+// parseRuntime returns the runtime described somewhere in text, e.g. "PG-13,
+// 2 hr 7 min", or 0 if there isn't one.
+func parseRuntime(text string) time.Duration {
+	for _, match := range runtimeRegex.FindAllStringSubmatch(text, -1) {
+		if match[1] == "" && match[2] == "" {
+			continue
+		}
+		var runtime time.Duration
+		if hours, err := strconv.Atoi(match[1]); err == nil {
+			runtime += time.Duration(hours) * time.Hour
+		}
+		if minutes, err := strconv.Atoi(match[2]); err == nil {
+			runtime += time.Duration(minutes) * time.Minute
+		}
+		return runtime
+	}
+	return 0
+}
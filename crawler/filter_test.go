@@ -0,0 +1,151 @@
+package crawler
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFilterShowings(t *testing.T) {
+	at := func(clocktime string, runtime time.Duration) Showing {
+		return Showing{Link: clocktime, When: clockTime(t, clocktime), Runtime: runtime}
+	}
+	imax := at("18:00", 2*time.Hour)
+	imax.Format = "IMAX 3D"
+	dolby := at("20:30", 2*time.Hour)
+	dolby.Format = FormatDolbyCinema
+	showings := []Showing{
+		at("10:00", 2*time.Hour),
+		imax,
+		dolby,
+		at("21:00", 0),
+		at("22:15", 90*time.Minute),
+	}
+
+	tcs := []struct {
+		name     string
+		req      Request
+		expected []string
+	}{{
+		name:     "no filters",
+		expected: []string{"10:00", "18:00", "20:30", "21:00", "22:15"},
+	}, {
+		name:     "after",
+		req:      Request{After: 18 * time.Hour},
+		expected: []string{"18:00", "20:30", "21:00", "22:15"},
+	}, {
+		name:     "before",
+		req:      Request{Before: 21 * time.Hour},
+		expected: []string{"10:00", "18:00", "20:30", "21:00"},
+	}, {
+		name:     "window",
+		req:      Request{After: 18 * time.Hour, Before: 22 * time.Hour},
+		expected: []string{"18:00", "20:30", "21:00"},
+	}, {
+		name:     "ends before",
+		req:      Request{EndsBefore: 23 * time.Hour},
+		expected: []string{"10:00", "18:00", "20:30", "21:00"},
+	}, {
+		name:     "formats",
+		req:      Request{Formats: []string{"imax", "dolby"}},
+		expected: []string{"18:00", "20:30"},
+	}, {
+		name:     "exclude formats",
+		req:      Request{ExcludeFormats: []string{"3d"}},
+		expected: []string{"10:00", "20:30", "21:00", "22:15"},
+	}, {
+		name:     "ends before keeps unknown runtimes",
+		req:      Request{EndsBefore: 22 * time.Hour},
+		expected: []string{"10:00", "18:00", "21:00"},
+	}}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			kept := filterShowings(tc.req, showings)
+			var links []string
+			for _, showing := range kept {
+				links = append(links, showing.Link)
+			}
+			if len(links) != len(tc.expected) {
+				t.Fatalf("got %v, want %v", links, tc.expected)
+			}
+			for i := range links {
+				if links[i] != tc.expected[i] {
+					t.Fatalf("got %v, want %v", links, tc.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestParseRuntime(t *testing.T) {
+	tcs := []struct {
+		text     string
+		expected time.Duration
+	}{
+		{text: "PG-13, 2 hr 7 min", expected: 2*time.Hour + 7*time.Minute},
+		{text: "R, 1 hr", expected: time.Hour},
+		{text: "95 min", expected: 95 * time.Minute},
+		{text: "2 hrs 30 mins", expected: 2*time.Hour + 30*time.Minute},
+		{text: "NR", expected: 0},
+		{text: "", expected: 0},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.text, func(t *testing.T) {
+			if got := parseRuntime(tc.text); got != tc.expected {
+				t.Errorf("parseRuntime(%q) = %s, want %s", tc.text, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestFilterTheaters(t *testing.T) {
+	showings := []Showing{
+		{Link: "state", Theater: "State Theatre", Distance: 0.3},
+		{Link: "amc", Theater: "AMC Ann Arbor 20", Distance: 6.2},
+		{Link: "michigan", Theater: "The Michigan Theater"},
+		{Link: "emagine", Theater: "Emagine Saline", Distance: 4.9},
+	}
+
+	tcs := []struct {
+		name      string
+		req       Request
+		expected  []string
+		favorites []string
+	}{{
+		name:     "include",
+		req:      Request{Theaters: []string{"state", "michigan theater"}},
+		expected: []string{"state", "michigan"},
+	}, {
+		name:     "exclude ignores case and punctuation",
+		req:      Request{ExcludeTheaters: []string{"amc ann-arbor"}},
+		expected: []string{"state", "michigan", "emagine"},
+	}, {
+		name:     "radius keeps unknown distances",
+		req:      Request{Radius: 5},
+		expected: []string{"state", "michigan", "emagine"},
+	}, {
+		name:      "favorites",
+		req:       Request{ExcludeTheaters: []string{"emagine"}, FavoriteTheaters: []string{"Michigan"}},
+		expected:  []string{"state", "amc", "michigan"},
+		favorites: []string{"michigan"},
+	}}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var links, favorites []string
+			for _, showing := range filterShowings(tc.req, showings) {
+				links = append(links, showing.Link)
+				if showing.Favorite {
+					favorites = append(favorites, showing.Link)
+				}
+			}
+			if !slices.Equal(links, tc.expected) {
+				t.Errorf("got %v, want %v", links, tc.expected)
+			}
+			if !slices.Equal(favorites, tc.favorites) {
+				t.Errorf("got favorites %v, want %v", favorites, tc.favorites)
+			}
+		})
+	}
+}
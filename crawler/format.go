@@ -0,0 +1,57 @@
+package crawler
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Formats a showing can be in. A showing may combine several, e.g. "IMAX 3D".
+const (
+	FormatStandard    = "Standard"
+	FormatIMAX        = "IMAX"
+	FormatDolbyCinema = "Dolby Cinema"
+	Format70mm        = "70mm"
+	Format3D          = "3D"
+)
+
+// Formats are the known formats.
+var Formats = []string{FormatStandard, FormatIMAX, FormatDolbyCinema, Format70mm, Format3D}
+
+// formatRegexes recognize formats in a variant's heading and amenities, in
+// the order they're named.
+var formatRegexes = []struct {
+	format string
+	regex  *regexp.Regexp
+}{
+	{FormatIMAX, regexp.MustCompile(`(?i)\bimax\b`)},
+	{FormatDolbyCinema, regexp.MustCompile(`(?i)\bdolby\s+cinema\b`)},
+	{Format70mm, regexp.MustCompile(`(?i)\b70\s*mm\b`)},
+	{Format3D, regexp.MustCompile(`(?i)\b(?:real\s*)?3-?d\b`)},
+}
+
+// parseFormat returns the format described by texts, e.g. a variant's heading
+// and amenities. It's FormatStandard if no other format is mentioned.
+func parseFormat(texts []string) string {
+	joined := strings.Join(texts, "\n")
+	var formats []string
+	for _, fr := range formatRegexes {
+		if fr.regex.MatchString(joined) {
+			formats = append(formats, fr.format)
+		}
+	}
+	if len(formats) == 0 {
+		return FormatStandard
+	}
+	return strings.Join(formats, " ")
+}
+
+// matchFormat returns whether format matches any of patterns, e.g. "dolby"
+// matches "Dolby Cinema" and "imax" matches "IMAX 3D".
+func matchFormat(format string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.Contains(strings.ToLower(format), strings.ToLower(pattern)) {
+			return true
+		}
+	}
+	return false
+}
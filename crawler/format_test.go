@@ -0,0 +1,70 @@
+package crawler
+
+import "testing"
+
+func TestParseFormat(t *testing.T) {
+	tcs := []struct {
+		name     string
+		texts    []string
+		expected string
+	}{{
+		name:     "standard",
+		texts:    []string{"Standard", "Reserved seating", "Closed caption"},
+		expected: FormatStandard,
+	}, {
+		name:     "no texts",
+		expected: FormatStandard,
+	}, {
+		name:     "imax",
+		texts:    []string{"IMAX with Laser", "Reserved seating"},
+		expected: FormatIMAX,
+	}, {
+		name:     "dolby cinema",
+		texts:    []string{"Dolby Cinema", "Recliner seating"},
+		expected: FormatDolbyCinema,
+	}, {
+		name:     "dolby atmos isn't a format",
+		texts:    []string{"Dolby Atmos"},
+		expected: FormatStandard,
+	}, {
+		name:     "70mm",
+		texts:    []string{"70MM Film"},
+		expected: Format70mm,
+	}, {
+		name:     "real 3d",
+		texts:    []string{"RealD 3D"},
+		expected: Format3D,
+	}, {
+		name:     "imax 3d",
+		texts:    []string{"IMAX 3D"},
+		expected: "IMAX 3D",
+	}}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := parseFormat(tc.texts); got != tc.expected {
+				t.Errorf("parseFormat(%q) = %q, want %q", tc.texts, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestMatchFormat(t *testing.T) {
+	tcs := []struct {
+		format   string
+		patterns []string
+		expected bool
+	}{
+		{format: FormatDolbyCinema, patterns: []string{"imax", "dolby"}, expected: true},
+		{format: "IMAX 3D", patterns: []string{"3d"}, expected: true},
+		{format: FormatStandard, patterns: []string{"imax", "dolby"}, expected: false},
+		{format: FormatStandard, patterns: nil, expected: false},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.format, func(t *testing.T) {
+			if got := matchFormat(tc.format, tc.patterns); got != tc.expected {
+				t.Errorf("matchFormat(%q, %q) = %t, want %t", tc.format, tc.patterns, got, tc.expected)
+			}
+		})
+	}
+}
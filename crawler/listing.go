@@ -0,0 +1,168 @@
+package crawler
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	playwright "github.com/playwright-community/playwright-go"
+)
+
+// showtimeRegex matches showtimes as listed, e.g. "9:30a" or "12:30p".
+var showtimeRegex = regexp.MustCompile(`(?i)\b(\d{1,2}:\d{2})\s*([ap])m?\b`)
+
+// parseShowtime returns the time of day in a showtime button's text, which is
+// a bunch of whitespace around a string like "9:30a", sometimes along with a
+// badge.
+func parseShowtime(text string) (time.Time, error) {
+	match := showtimeRegex.FindStringSubmatch(text)
+	if match == nil {
+		return time.Time{}, fmt.Errorf("no showtime in %q", strings.TrimSpace(text))
+	}
+	return time.Parse("3:04pm", match[1]+strings.ToLower(match[2])+"m")
+}
+
+// badgeRegex matches the hints the search page gives about how many seats are
+// left.
+var badgeRegex = regexp.MustCompile(`(?i)\b(?:almost sold out|sold out|selling fast|(?:only )?a few (?:seats )?left|few seats left|limited (?:seating|availability))\b`)
+
+// parseBadge returns the availability hint in text, e.g. "Selling fast", or ""
+// if there isn't one.
+func parseBadge(text string) string {
+	badge := badgeRegex.FindString(text)
+	if badge == "" {
+		return ""
+	}
+	return strings.ToUpper(badge[:1]) + strings.ToLower(badge[1:])
+}
+
+// kmPerMile converts kilometers to miles.
+const kmPerMile = 1.609344
+
+// distanceUnits are the units a distance can be given in.
+const distanceUnits = `mi|miles?|km|kilometers?`
+
+// distanceRegex matches distances like "2.3 mi" or "12 km" within text.
+var distanceRegex = regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)\s*(` + distanceUnits + `)\b`)
+
+// exactDistanceRegex matches a whole string that's a distance, with the unit
+// optional.
+var exactDistanceRegex = regexp.MustCompile(`(?i)^(\d+(?:\.\d+)?)\s*(` + distanceUnits + `)?$`)
+
+// ParseDistance returns the distance in miles described by input, e.g. "10mi",
+// "10 miles" or "16km". A bare number is in miles.
+func ParseDistance(input string) (float64, error) {
+	match := exactDistanceRegex.FindStringSubmatch(strings.TrimSpace(input))
+	if match == nil {
+		return 0, fmt.Errorf(`invalid distance %q: use e.g. "10mi" or "15km"`, input)
+	}
+	distance, err := toMiles(match[1], match[2])
+	if err != nil {
+		return 0, fmt.Errorf("invalid distance %q: %w", input, err)
+	}
+	return distance, nil
+}
+
+// parseDistance returns the distance in miles described in text, e.g. "2.3
+// mi", or 0 if there isn't one.
+func parseDistance(text string) float64 {
+	match := distanceRegex.FindStringSubmatch(text)
+	if match == nil {
+		return 0
+	}
+	distance, err := toMiles(match[1], match[2])
+	if err != nil {
+		return 0
+	}
+	return distance
+}
+
+// toMiles converts number, in unit, to miles.
+func toMiles(number, unit string) (float64, error) {
+	distance, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, err
+	}
+	if strings.HasPrefix(strings.ToLower(unit), "k") {
+		distance /= kmPerMile
+	}
+	return distance, nil
+}
+
+// theaterLocation returns the address and distance in miles listed for
+// theater, if any. Neither is essential, so they're read without waiting.
+func theaterLocation(theater playwright.Locator) (address string, distance float64) {
+	if nodes, err := theater.Locator(".fd-theater__address").All(); err == nil && len(nodes) > 0 {
+		if text, err := nodes[0].TextContent(); err == nil {
+			address = strings.Join(strings.Fields(text), " ")
+		}
+	}
+	if nodes, err := theater.Locator(".fd-theater__distance").All(); err == nil && len(nodes) > 0 {
+		if text, err := nodes[0].TextContent(); err == nil {
+			distance = parseDistance(text)
+		}
+	}
+	return address, distance
+}
+
+// theaterSelector selects the theaters listed on the search page.
+const theaterSelector = ".fd-showtimes .fd-theater"
+
+// loadMoreRegex matches the names of controls that list more theaters.
+var loadMoreRegex = regexp.MustCompile(`(?i)^\s*(?:load|show|see|view) more(?: theaters)?\s*$`)
+
+// expandTheaters gets the search page to list up to maxTheaters theaters, or
+// all of them if maxTheaters is 0, and returns them. Dense areas list only some
+// theaters at first, with more behind a "load more" control or loaded as the
+// page is scrolled.
+func expandTheaters(page *rateLimitedPage, maxTheaters int) ([]playwright.Locator, error) {
+	theaters := page.Locator(theaterSelector)
+	loadMore := page.GetByRole("button", playwright.PageGetByRoleOptions{Name: loadMoreRegex}).First()
+	for {
+		count, err := theaters.Count()
+		if err != nil {
+			return nil, fmt.Errorf("failed to count theaters: %w", err)
+		}
+		if count == 0 || (maxTheaters > 0 && count >= maxTheaters) {
+			break
+		}
+
+		// Clicking loads from the site, so it's paced like any other
+		// load. Scrolling is just what a person would do anyway.
+		timeout := 5 * time.Second
+		if visible, err := loadMore.IsVisible(); err == nil && visible {
+			if err := page.pacer.wait(page.ctx); err != nil {
+				return nil, err
+			}
+			page.logger.Debug("loading more theaters", "theaters", count)
+			if err := loadMore.Click(); err != nil {
+				page.logger.Info("failed to load more theaters", "theaters", count, "err", err)
+				break
+			}
+			timeout = 30 * time.Second
+		} else if _, err := page.Evaluate("window.scrollTo(0, document.body.scrollHeight)"); err != nil {
+			page.logger.Debug("failed to scroll for more theaters", "err", err)
+			break
+		}
+
+		timeoutMS := float64(timeout.Milliseconds())
+		if err := theaters.Nth(count).WaitFor(playwright.LocatorWaitForOptions{
+			State:   playwright.WaitForSelectorStateAttached,
+			Timeout: &timeoutMS,
+		}); err != nil {
+			// There aren't any more.
+			break
+		}
+	}
+
+	all, err := theaters.All()
+	if err != nil {
+		return nil, err
+	}
+	if maxTheaters > 0 && len(all) > maxTheaters {
+		all = all[:maxTheaters]
+	}
+	return all, nil
+}
@@ -0,0 +1,132 @@
+package crawler
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseShowtime(t *testing.T) {
+	tcs := []struct {
+		text        string
+		expected    string
+		expectError bool
+	}{
+		{text: "\n   9:30a  \n", expected: "09:30"},
+		{text: "12:30p", expected: "12:30"},
+		{text: "7:05p Selling Fast", expected: "19:05"},
+		{text: "10:45PM", expected: "22:45"},
+		{text: "Sold out", expectError: true},
+		{text: "", expectError: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.text, func(t *testing.T) {
+			showtime, err := parseShowtime(tc.text)
+			if err != nil {
+				if !tc.expectError {
+					t.Fatalf("parseShowtime(%q) returned error: %v", tc.text, err)
+				}
+				return
+			}
+			if tc.expectError {
+				t.Fatalf("parseShowtime(%q) = %s, expected error", tc.text, showtime)
+			}
+			if got := showtime.Format("15:04"); got != tc.expected {
+				t.Errorf("parseShowtime(%q) = %s, want %s", tc.text, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseBadge(t *testing.T) {
+	tcs := []struct {
+		text     string
+		expected string
+	}{
+		{text: "7:00p SELLING FAST", expected: "Selling fast"},
+		{text: "7:00p\n  Sold Out", expected: "Sold out"},
+		{text: "Almost sold out", expected: "Almost sold out"},
+		{text: "Only a few seats left!", expected: "Only a few seats left"},
+		{text: "7:00p", expected: ""},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.text, func(t *testing.T) {
+			if got := parseBadge(tc.text); got != tc.expected {
+				t.Errorf("parseBadge(%q) = %q, want %q", tc.text, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseDistanceExact(t *testing.T) {
+	tcs := []struct {
+		input       string
+		expected    float64
+		expectError bool
+	}{
+		{input: "10mi", expected: 10},
+		{input: "10 Miles", expected: 10},
+		{input: "7.5", expected: 7.5},
+		{input: " 16.09344km ", expected: 10},
+		{input: "10 furlongs", expectError: true},
+		{input: "2.3 mi away", expectError: true},
+		{input: "", expectError: true},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := ParseDistance(tc.input)
+			if err != nil {
+				if tc.expectError {
+					return
+				}
+				t.Fatalf("ParseDistance(%q) returned error: %v", tc.input, err)
+			}
+			if tc.expectError {
+				t.Fatalf("expected error, but none found")
+			}
+			if math.Abs(got-tc.expected) > 1e-9 {
+				t.Errorf("ParseDistance(%q) = %v, want %v", tc.input, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestParseDistance(t *testing.T) {
+	tcs := []struct {
+		text     string
+		expected float64
+	}{
+		{text: "2.3 mi", expected: 2.3},
+		{text: "\n  12 Miles away ", expected: 12},
+		{text: "0.4mi", expected: 0.4},
+		{text: "16.09344 km", expected: 10},
+		{text: "nearby", expected: 0},
+		{text: "", expected: 0},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.text, func(t *testing.T) {
+			if got := parseDistance(tc.text); math.Abs(got-tc.expected) > 1e-9 {
+				t.Errorf("parseDistance(%q) = %v, want %v", tc.text, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestLoadMoreRegex(t *testing.T) {
+	tcs := []struct {
+		name     string
+		expected bool
+	}{
+		{name: "Load More", expected: true},
+		{name: "See more theaters", expected: true},
+		{name: " show more ", expected: true},
+		{name: "Learn more about IMAX", expected: false},
+		{name: "More", expected: false},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := loadMoreRegex.MatchString(tc.name); got != tc.expected {
+				t.Errorf("loadMoreRegex matches %q = %t, want %t", tc.name, got, tc.expected)
+			}
+		})
+	}
+}
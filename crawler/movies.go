@@ -0,0 +1,89 @@
+package crawler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// A Movie summarizes the showings of one movie.
+type Movie struct {
+	Title string
+	// Theaters is the number of theaters showing it.
+	Theaters int
+	// Showings is the number of showings across all theaters.
+	Showings int
+	// Formats are the formats it's shown in, e.g. FormatIMAX.
+	Formats []string
+	// Earliest and Latest are the first and last showtimes.
+	Earliest time.Time
+	Latest   time.Time
+}
+
+// Movies lists the movies playing near req.Zip on req.Dates. It only loads
+// search pages, so it's much cheaper than a crawl. See Client.Movies.
+func Movies(ctx context.Context, req Request) ([]Movie, error) {
+	client, err := NewClient(ClientOptions{Logger: req.Logger})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start browser: %w", err)
+	}
+	defer closeClient(client)
+	return client.Movies(ctx, req)
+}
+
+// Movies lists the movies playing near req.Zip on req.Dates, or only those
+// matching req.Titles if there are any. Showings are filtered as in a crawl.
+func (c *Client) Movies(ctx context.Context, req Request) ([]Movie, error) {
+	c.crawlMu.Lock()
+	defer c.crawlMu.Unlock()
+	c.pacer.configure(req)
+
+	// Listing several matches is the point.
+	req.AllMatches = true
+	res, err := c.showings(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get showings: %w", err)
+	}
+	return summarizeMovies(filterShowings(req, res.Showings)), nil
+}
+
+// summarizeMovies groups showings by movie, sorted by title. A movie listed
+// under slightly different titles, e.g. "Sunny (2024)" and "Sunny", is
+// grouped under the first one seen.
+func summarizeMovies(showings []Showing) []Movie {
+	var movies []Movie
+	indexes := make(map[string]int)
+	theaters := make(map[string]map[string]bool)
+	for _, showing := range showings {
+		key := movieKey(showing.Movie)
+		i, ok := indexes[key]
+		if !ok {
+			i = len(movies)
+			indexes[key] = i
+			theaters[key] = make(map[string]bool)
+			movies = append(movies, Movie{Title: showing.Movie, Earliest: showing.When, Latest: showing.When})
+		}
+		movie := &movies[i]
+		movie.Showings++
+		theaters[key][showing.Theater] = true
+		movie.Theaters = len(theaters[key])
+		if showing.Format != "" && !slices.Contains(movie.Formats, showing.Format) {
+			movie.Formats = append(movie.Formats, showing.Format)
+		}
+		if showing.When.Before(movie.Earliest) {
+			movie.Earliest = showing.When
+		}
+		if showing.When.After(movie.Latest) {
+			movie.Latest = showing.When
+		}
+	}
+	for i := range movies {
+		slices.Sort(movies[i].Formats)
+	}
+	slices.SortFunc(movies, func(a, b Movie) int {
+		return strings.Compare(movieKey(a.Title), movieKey(b.Title))
+	})
+	return movies
+}
@@ -0,0 +1,39 @@
+package crawler
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSummarizeMovies(t *testing.T) {
+	showings := []Showing{
+		{Movie: "Sunny (2024)", Theater: "State", When: clockTime(t, "19:00"), Format: FormatStandard},
+		{Movie: "The Wild Robot", Theater: "State", When: clockTime(t, "13:00"), Format: FormatStandard},
+		{Movie: "Sunny (2024)", Theater: "State", When: clockTime(t, "21:30"), Format: FormatIMAX},
+		{Movie: "Sunny", Theater: "Michigan", When: clockTime(t, "14:15"), Format: FormatStandard},
+	}
+
+	movies := summarizeMovies(showings)
+	if len(movies) != 2 {
+		t.Fatalf("summarizeMovies() returned %d movies, want 2: %+v", len(movies), movies)
+	}
+
+	sunny := movies[0]
+	if sunny.Title != "Sunny (2024)" {
+		t.Errorf("first movie is %q, want %q", sunny.Title, "Sunny (2024)")
+	}
+	if sunny.Theaters != 2 || sunny.Showings != 3 {
+		t.Errorf("Sunny has %d theaters and %d showings, want 2 and 3", sunny.Theaters, sunny.Showings)
+	}
+	if want := []string{FormatIMAX, FormatStandard}; !slices.Equal(sunny.Formats, want) {
+		t.Errorf("Sunny has formats %q, want %q", sunny.Formats, want)
+	}
+	if !sunny.Earliest.Equal(clockTime(t, "14:15")) || !sunny.Latest.Equal(clockTime(t, "21:30")) {
+		t.Errorf("Sunny plays from %s to %s, want 14:15 to 21:30", sunny.Earliest.Format("15:04"), sunny.Latest.Format("15:04"))
+	}
+
+	// Sorted ignoring the leading article.
+	if robot := movies[1]; robot.Title != "The Wild Robot" || robot.Theaters != 1 || robot.Showings != 1 {
+		t.Errorf("second movie is %+v", robot)
+	}
+}
@@ -0,0 +1,76 @@
+package crawler
+
+import "time"
+
+// An Observer receives events as a crawl progresses. Observe is called
+// synchronously from the crawl, so it should return quickly.
+type Observer interface {
+	Observe(Event)
+}
+
+// ObserverFunc adapts a function to an Observer.
+type ObserverFunc func(Event)
+
+// Observe calls f(ev).
+func (f ObserverFunc) Observe(ev Event) {
+	f(ev)
+}
+
+// An Event is one of the *Event types in this package.
+type Event interface {
+	event()
+}
+
+// SearchStartedEvent is sent when a search page is requested.
+type SearchStartedEvent struct {
+	URL string
+}
+
+// TheaterParsedEvent is sent when a theater's listings have been parsed.
+type TheaterParsedEvent struct {
+	Theater string
+	// NumShowings is the number of matching showings at the theater.
+	NumShowings int
+}
+
+// ShowingQueuedEvent is sent when a showing is queued to have its seats
+// checked.
+type ShowingQueuedEvent struct {
+	Showing Showing
+}
+
+// SeatMapLoadedEvent is sent when a showing's seats have been checked.
+// Showing.Verdict holds the outcome.
+type SeatMapLoadedEvent struct {
+	Showing Showing
+}
+
+// RetryEvent is sent when a showing's seats couldn't be checked and will be
+// retried after Delay.
+type RetryEvent struct {
+	Showing Showing
+	Err     error
+	Delay   time.Duration
+}
+
+// ShowingFailedEvent is sent when a showing's seats couldn't be checked and
+// won't be retried.
+type ShowingFailedEvent struct {
+	Showing Showing
+	Err     error
+}
+
+// DoneEvent is sent when every queued showing has been checked.
+type DoneEvent struct {
+	Good   int
+	Bad    int
+	Failed int
+}
+
+func (SearchStartedEvent) event() {}
+func (TheaterParsedEvent) event() {}
+func (ShowingQueuedEvent) event() {}
+func (SeatMapLoadedEvent) event() {}
+func (RetryEvent) event()         {}
+func (ShowingFailedEvent) event() {}
+func (DoneEvent) event()          {}
@@ -0,0 +1,83 @@
+package crawler
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// CrawlOrder decides which showings have their seats checked first. It
+// matters most when only some showings are checked, e.g. because of
+// Request.ShowingLimit or Request.StopAfter.
+type CrawlOrder int
+
+const (
+	// OrderListed checks showings in the order the search lists them.
+	OrderListed CrawlOrder = iota
+	// OrderTime checks the earliest showings first.
+	OrderTime
+	// OrderTheater checks showings theater by theater.
+	OrderTheater
+	// OrderPreferred checks the showings nearest Request.PreferredTime
+	// first.
+	OrderPreferred
+)
+
+// sortShowings returns a copy of showings in the order req asks for, with
+// showings at favorite theaters first.
+func sortShowings(req Request, showings []Showing) []Showing {
+	sorted := slices.Clone(showings)
+	switch req.Order {
+	case OrderTime:
+		slices.SortStableFunc(sorted, func(a, b Showing) int {
+			if cmp := a.When.Compare(b.When); cmp != 0 {
+				return cmp
+			}
+			return a.Compare(b)
+		})
+	case OrderTheater:
+		slices.SortStableFunc(sorted, func(a, b Showing) int {
+			return a.Compare(b)
+		})
+	case OrderPreferred:
+		slices.SortStableFunc(sorted, func(a, b Showing) int {
+			da, db := distance(clock(a.When), req.PreferredTime), distance(clock(b.When), req.PreferredTime)
+			if c := cmp.Compare(da, db); c != 0 {
+				return c
+			}
+			if c := a.When.Compare(b.When); c != 0 {
+				return c
+			}
+			return a.Compare(b)
+		})
+	}
+	slices.SortStableFunc(sorted, func(a, b Showing) int {
+		return compareFavorite(a, b)
+	})
+	return sorted
+}
+
+// compareFavorite orders showings at favorite theaters first.
+func compareFavorite(a, b Showing) int {
+	switch {
+	case a.Favorite == b.Favorite:
+		return 0
+	case a.Favorite:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// clock returns the time of day of t as the time since midnight.
+func clock(t time.Time) time.Duration {
+	hour, min, sec := t.Clock()
+	return time.Duration(hour)*time.Hour + time.Duration(min)*time.Minute + time.Duration(sec)*time.Second
+}
+
+func distance(a, b time.Duration) time.Duration {
+	if a < b {
+		return b - a
+	}
+	return a - b
+}
@@ -0,0 +1,73 @@
+package crawler
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSortShowings(t *testing.T) {
+	showings := []Showing{
+		{Link: "b-1900", Theater: "B", When: clockTime(t, "19:00")},
+		{Link: "a-2130", Theater: "A", When: clockTime(t, "21:30")},
+		{Link: "b-1400", Theater: "B", When: clockTime(t, "14:00")},
+		{Link: "a-1900", Theater: "A", When: clockTime(t, "19:00")},
+	}
+
+	tcs := []struct {
+		name      string
+		order     CrawlOrder
+		preferred time.Duration
+		expected  []string
+	}{{
+		name:     "listed",
+		order:    OrderListed,
+		expected: []string{"b-1900", "a-2130", "b-1400", "a-1900"},
+	}, {
+		name:     "time",
+		order:    OrderTime,
+		expected: []string{"b-1400", "a-1900", "b-1900", "a-2130"},
+	}, {
+		name:     "theater",
+		order:    OrderTheater,
+		expected: []string{"a-1900", "a-2130", "b-1400", "b-1900"},
+	}, {
+		name:      "preferred",
+		order:     OrderPreferred,
+		preferred: 20*time.Hour + 45*time.Minute,
+		expected:  []string{"a-2130", "a-1900", "b-1900", "b-1400"},
+	}}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			sorted := sortShowings(Request{Order: tc.order, PreferredTime: tc.preferred}, showings)
+			var links []string
+			for _, showing := range sorted {
+				links = append(links, showing.Link)
+			}
+			if len(links) != len(tc.expected) {
+				t.Fatalf("got %v, want %v", links, tc.expected)
+			}
+			for i := range links {
+				if links[i] != tc.expected[i] {
+					t.Fatalf("got %v, want %v", links, tc.expected)
+				}
+			}
+		})
+	}
+	// Favorites come first, in the requested order.
+	favorites := slices.Clone(showings)
+	favorites[0].Favorite = true
+	favorites[2].Favorite = true
+	var links []string
+	for _, showing := range sortShowings(Request{Order: OrderTime}, favorites) {
+		links = append(links, showing.Link)
+	}
+	if expected := []string{"b-1400", "b-1900", "a-1900", "a-2130"}; !slices.Equal(links, expected) {
+		t.Errorf("with favorites, got %v, want %v", links, expected)
+	}
+
+	if showings[0].Link != "b-1900" {
+		t.Errorf("sortShowings() modified its input")
+	}
+}
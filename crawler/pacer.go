@@ -0,0 +1,123 @@
+package crawler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	// slowResponse is how long a page load can take before we assume the
+	// site is struggling.
+	slowResponse = 10 * time.Second
+	// failureStreak is how many consecutive failures widen the interval.
+	failureStreak = 2
+	// successStreak is how many consecutive successes narrow the interval.
+	successStreak = 5
+	// widenFactor and narrowFactor scale the interval when it's adapted.
+	widenFactor  = 2.0
+	narrowFactor = 0.75
+	// maxScale caps how far the interval can be widened.
+	maxScale = 8.0
+)
+
+// A pacer spaces out page loads. Every page loaded by a Client shares one
+// pacer, so pages are rate limited across crawls.
+//
+// An adaptive pacer widens the interval when the site seems unhappy and eases
+// back toward the configured interval once it recovers.
+type pacer struct {
+	interval DurationRange
+	adaptive bool
+	logger   *slog.Logger
+
+	last      time.Time
+	scale     float64
+	failures  int
+	successes int
+}
+
+func newPacer(interval DurationRange, adaptive bool, logger *slog.Logger) *pacer {
+	return &pacer{
+		interval: interval,
+		adaptive: adaptive,
+		logger:   logger,
+		scale:    1,
+	}
+}
+
+// configure sets the interval and whether it's adaptive for req, keeping any
+// adaptation so far.
+func (pc *pacer) configure(req Request) {
+	pc.interval = req.RequestInterval
+	pc.adaptive = req.AdaptivePacing
+	pc.logger = req.logger()
+}
+
+// wait sleeps until the next page can be loaded or ctx is done.
+func (pc *pacer) wait(ctx context.Context) error {
+	if !pc.last.IsZero() {
+		timer := time.NewTimer(time.Until(pc.last.Add(pc.next())))
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	pc.last = time.Now()
+	return nil
+}
+
+// next returns a random wait from the current interval.
+func (pc *pacer) next() time.Duration {
+	return time.Duration(float64(pc.interval.Random()) * pc.scale)
+}
+
+// loaded records a page load that took elapsed and returned status. Statuses
+// asking us to back off and slow loads widen the interval right away.
+func (pc *pacer) loaded(status int, elapsed time.Duration) {
+	// Wait between the end of one load and the start of the next.
+	pc.last = time.Now()
+	switch {
+	case status == http.StatusTooManyRequests, status == http.StatusServiceUnavailable:
+		pc.widen("status", status)
+	case elapsed > slowResponse:
+		pc.widen("elapsed", elapsed)
+	}
+}
+
+// succeeded records a page that was crawled successfully.
+func (pc *pacer) succeeded() {
+	pc.failures = 0
+	pc.successes++
+	if !pc.adaptive || pc.successes < successStreak || pc.scale == 1 {
+		return
+	}
+	pc.successes = 0
+	pc.scale = max(1, pc.scale*narrowFactor)
+	pc.logger.Debug("speeding up requests", "scale", pc.scale)
+}
+
+// failed records a page that couldn't be crawled.
+func (pc *pacer) failed() {
+	pc.successes = 0
+	pc.failures++
+	if pc.failures >= failureStreak {
+		pc.widen("consecutiveFailures", pc.failures)
+	}
+}
+
+func (pc *pacer) widen(reasonKey string, reason any) {
+	if !pc.adaptive {
+		return
+	}
+	pc.failures = 0
+	pc.successes = 0
+	if pc.scale >= maxScale {
+		return
+	}
+	pc.scale = min(maxScale, pc.scale*widenFactor)
+	pc.logger.Info("slowing down requests", reasonKey, reason, "scale", pc.scale)
+}
@@ -0,0 +1,74 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPacer(t *testing.T) {
+	interval := DurationRange{Lower: time.Second, Upper: time.Second}
+	pc := newPacer(interval, true /* adaptive */, slog.Default())
+
+	pc.loaded(http.StatusOK, time.Second)
+	pc.failed()
+	if pc.scale != 1 {
+		t.Fatalf("scale is %f after a single failure, want 1", pc.scale)
+	}
+	pc.failed()
+	if pc.scale != 2 {
+		t.Fatalf("scale is %f after consecutive failures, want 2", pc.scale)
+	}
+	pc.loaded(http.StatusTooManyRequests, time.Second)
+	if pc.scale != 4 {
+		t.Fatalf("scale is %f after HTTP 429, want 4", pc.scale)
+	}
+	pc.loaded(http.StatusOK, time.Minute)
+	if pc.scale != 8 {
+		t.Fatalf("scale is %f after a slow response, want 8", pc.scale)
+	}
+	pc.loaded(http.StatusServiceUnavailable, time.Second)
+	if pc.scale != maxScale {
+		t.Fatalf("scale is %f, want it capped at %f", pc.scale, maxScale)
+	}
+	if got, want := pc.next(), 8*time.Second; got != want {
+		t.Fatalf("next() = %s, want %s", got, want)
+	}
+
+	// Ease back to the configured interval.
+	for range 100 * successStreak {
+		pc.succeeded()
+	}
+	if pc.scale != 1 {
+		t.Fatalf("scale is %f after many successes, want 1", pc.scale)
+	}
+}
+
+func TestPacerNotAdaptive(t *testing.T) {
+	interval := DurationRange{Lower: time.Second, Upper: time.Second}
+	pc := newPacer(interval, false /* adaptive */, slog.Default())
+	for range 10 {
+		pc.failed()
+		pc.loaded(http.StatusTooManyRequests, time.Minute)
+	}
+	if got, want := pc.next(), time.Second; got != want {
+		t.Fatalf("next() = %s, want %s", got, want)
+	}
+}
+
+func TestPacerWaitCanceled(t *testing.T) {
+	interval := DurationRange{Lower: time.Hour, Upper: time.Hour}
+	pc := newPacer(interval, false /* adaptive */, slog.Default())
+	if err := pc.wait(context.Background()); err != nil {
+		t.Fatalf("first wait() = %v, want nil", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := pc.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("wait() = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,79 @@
+package crawler
+
+import (
+	"errors"
+	"time"
+
+	"golang.org/x/exp/rand"
+)
+
+// A RetryPolicy controls how failed seat crawls are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the max number of times to crawl a showing's seats.
+	// Values below 1 mean a single attempt.
+	MaxAttempts int
+	// Backoff is the delay before the first retry of a showing. It doubles
+	// with each subsequent failure.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between retries. Zero means no cap.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is a reasonable RetryPolicy for most searches.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	Backoff:     30 * time.Second,
+	MaxBackoff:  5 * time.Minute,
+}
+
+// shouldRetry returns whether a showing should be tried again after its
+// attempts so far, the last of which returned err.
+func (rp RetryPolicy) shouldRetry(attempts int, err error) bool {
+	return attempts < rp.MaxAttempts && retryable(err)
+}
+
+// delay returns how long to wait before retrying a showing that has failed
+// failures times. Half the delay is random jitter so that retries don't
+// line up.
+func (rp RetryPolicy) delay(failures int) time.Duration {
+	delay := rp.Backoff
+	for i := 1; i < failures; i++ {
+		if rp.MaxBackoff != 0 && delay >= rp.MaxBackoff {
+			break
+		}
+		delay *= 2
+	}
+	if rp.MaxBackoff != 0 {
+		delay = min(delay, rp.MaxBackoff)
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// retryable returns whether err is worth retrying. Timeouts and load failures
+// are often transient, but a page that loaded and didn't make sense will make
+// just as little sense next time. And if we're blocked, hammering the site
+// only makes it worse.
+func retryable(err error) bool {
+	var parseErr *ParseError
+	switch {
+	case errors.Is(err, ErrNoSeats), errors.Is(err, ErrBlocked), errors.As(err, &parseErr):
+		return false
+	default:
+		return true
+	}
+}
+
+// An Attempt is a single try at crawling a showing's seats.
+type Attempt struct {
+	// When is when the attempt started.
+	When time.Time
+	// Err is the error the attempt failed with, or nil if it succeeded.
+	Err error
+	// Diagnostics is the diagnostics bundle saved for a failed attempt, if
+	// any.
+	Diagnostics string
+}
@@ -0,0 +1,103 @@
+package crawler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	playwright "github.com/playwright-community/playwright-go"
+)
+
+func TestRetryDelay(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts: 10,
+		Backoff:     10 * time.Second,
+		MaxBackoff:  time.Minute,
+	}
+	tcs := []struct {
+		failures int
+		lower    time.Duration
+		upper    time.Duration
+	}{
+		{failures: 1, lower: 5 * time.Second, upper: 10 * time.Second},
+		{failures: 2, lower: 10 * time.Second, upper: 20 * time.Second},
+		{failures: 3, lower: 20 * time.Second, upper: 40 * time.Second},
+		{failures: 4, lower: 30 * time.Second, upper: time.Minute},
+		{failures: 100, lower: 30 * time.Second, upper: time.Minute},
+	}
+	for _, tc := range tcs {
+		t.Run(fmt.Sprintf("%d failures", tc.failures), func(t *testing.T) {
+			for range 100 {
+				if got := policy.delay(tc.failures); got < tc.lower || got > tc.upper {
+					t.Fatalf("delay(%d) = %s, want within [%s, %s]", tc.failures, got, tc.lower, tc.upper)
+				}
+			}
+		})
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3}
+	tcs := []struct {
+		name     string
+		attempts int
+		err      error
+		want     bool
+	}{
+		{
+			name:     "timeout",
+			attempts: 1,
+			err:      fmt.Errorf("failed to wait for seats: %w", playwright.ErrTimeout),
+			want:     true,
+		},
+		{
+			name:     "out of attempts",
+			attempts: 3,
+			err:      playwright.ErrTimeout,
+			want:     false,
+		},
+		{
+			name:     "no seats",
+			attempts: 1,
+			err:      fmt.Errorf("%w with link: %q", ErrNoSeats, "https://example.com"),
+			want:     false,
+		},
+		{
+			name:     "seat map timeout",
+			attempts: 1,
+			err:      fmt.Errorf("%w: %w", ErrSeatMapTimeout, playwright.ErrTimeout),
+			want:     true,
+		},
+		{
+			name:     "unparsable",
+			attempts: 1,
+			err: fmt.Errorf("failed to check seat: %w", &ParseError{
+				URL:      "https://example.com",
+				Selector: ".seat-map__seat",
+				Err:      errors.New("unknown aria-disabled attribute"),
+			}),
+			want: false,
+		},
+		{
+			name:     "blocked",
+			attempts: 1,
+			err:      fmt.Errorf("%w: HTTP 429", ErrBlocked),
+			want:     false,
+		},
+		{
+			name:     "rate limited",
+			attempts: 1,
+			err:      fmt.Errorf("failed to load page: %w", statusError("https://example.com", http.StatusTooManyRequests, true /* adaptive */)),
+			want:     true,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := policy.shouldRetry(tc.attempts, tc.err); got != tc.want {
+				t.Errorf("shouldRetry(%d, %v) = %t, want %t", tc.attempts, tc.err, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package crawler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// A Snapshot is a showing's seat map at a point in time. Snapshots are saved
+// as JSON, one file per showing, so that they can be evaluated again later
+// with different criteria and without touching the network.
+type Snapshot struct {
+	Showing  Showing        `json:"showing"`
+	Captured time.Time      `json:"captured"`
+	Seats    []SnapshotSeat `json:"seats"`
+}
+
+// A SnapshotSeat is a single seat in a Snapshot. Rows and columns count from
+// zero at the front left of the auditorium.
+type SnapshotSeat struct {
+	Row      int  `json:"row"`
+	Col      int  `json:"col"`
+	Reserved bool `json:"reserved"`
+}
+
+func newSnapshot(showing Showing, sm seatMap, captured time.Time) Snapshot {
+	showing.Verdict = VerdictUnknown
+	snapshot := Snapshot{Showing: showing, Captured: captured}
+	for _, st := range sm.seats {
+		snapshot.Seats = append(snapshot.Seats, SnapshotSeat{Row: st.row, Col: st.col, Reserved: st.reserved})
+	}
+	return snapshot
+}
+
+// Evaluate returns the snapshot's showing with its Verdict set according to
+// the seating criteria in req.
+func (sn *Snapshot) Evaluate(req Request) Showing {
+	var sm seatMap
+	for _, st := range sn.Seats {
+		sm.seats = append(sm.seats, seat{row: st.Row, col: st.Col, reserved: st.Reserved})
+		sm.maxRow = max(sm.maxRow, st.Row)
+		sm.maxCol = max(sm.maxCol, st.Col)
+	}
+	showing := sn.Showing
+	if checkSeats(sm.seats, sm.maxRow, sm.maxCol, req.NumSeats) {
+		showing.Verdict = VerdictGood
+	} else {
+		showing.Verdict = VerdictBad
+	}
+	return showing
+}
+
+// SaveSnapshot writes sn to dir, replacing any earlier snapshot of the same
+// showing. It returns the file written.
+func SaveSnapshot(dir string, sn Snapshot) (string, error) {
+	data, err := json.MarshalIndent(sn, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	// The link tells apart showings at the same theater and time, e.g. in
+	// different formats.
+	sum := sha256.Sum256([]byte(sn.Showing.Link))
+	name := fmt.Sprintf("%s-%s.json", bundleName(fmt.Sprintf("%s %s", sn.Showing.Theater, sn.Showing.When.Format("2006-01-02 1504"))), hex.EncodeToString(sum[:4]))
+	path := filepath.Join(dir, name)
+	if err := writeFileAtomic(path, data); err != nil {
+		return "", fmt.Errorf("failed to save snapshot: %w", err)
+	}
+	return path, nil
+}
+
+// LoadSnapshots reads every snapshot in dir.
+func LoadSnapshots(dir string) ([]Snapshot, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	snapshots := make([]Snapshot, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot: %w", err)
+		}
+		var snapshot Snapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return nil, fmt.Errorf("failed to decode snapshot %q: %w", path, err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
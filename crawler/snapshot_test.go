@@ -0,0 +1,56 @@
+package crawler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnapshots(t *testing.T) {
+	// A 7x8 theater with two free seats in the middle, like the "enough
+	// space" case in TestGoodSeats.
+	var sm seatMap
+	for row := range 7 {
+		for col := range 8 {
+			free := row == 3 && (col == 3 || col == 4)
+			sm.seats = append(sm.seats, seat{row: row, col: col, reserved: !free})
+		}
+	}
+	sm.maxRow = 6
+	sm.maxCol = 7
+
+	when := time.Date(2026, 10, 18, 19, 30, 0, 0, time.UTC)
+	showing := Showing{Link: "https://example.com/seats", Theater: "State Theatre", When: when, Verdict: VerdictGood}
+	dir := t.TempDir()
+	if _, err := SaveSnapshot(dir, newSnapshot(showing, sm, when)); err != nil {
+		t.Fatalf("SaveSnapshot() returned error: %v", err)
+	}
+	// Saving the same showing again replaces the old snapshot.
+	if _, err := SaveSnapshot(dir, newSnapshot(showing, sm, when.Add(time.Minute))); err != nil {
+		t.Fatalf("SaveSnapshot() returned error: %v", err)
+	}
+
+	snapshots, err := LoadSnapshots(dir)
+	if err != nil {
+		t.Fatalf("LoadSnapshots() returned error: %v", err)
+	}
+	if len(snapshots) != 1 {
+		t.Fatalf("LoadSnapshots() returned %d snapshots, want 1", len(snapshots))
+	}
+	snapshot := snapshots[0]
+	if snapshot.Showing.Link != showing.Link || snapshot.Showing.Theater != showing.Theater || !snapshot.Showing.When.Equal(when) {
+		t.Errorf("loaded showing %+v, want %+v", snapshot.Showing, showing)
+	}
+
+	if got := snapshot.Evaluate(Request{NumSeats: 2}); got.Verdict != VerdictGood {
+		t.Errorf("Evaluate() with 2 seats returned verdict %d, want %d", got.Verdict, VerdictGood)
+	}
+	if got := snapshot.Evaluate(Request{NumSeats: 3}); got.Verdict != VerdictBad {
+		t.Errorf("Evaluate() with 3 seats returned verdict %d, want %d", got.Verdict, VerdictBad)
+	}
+}
+
+func TestLoadSnapshotsMissingDir(t *testing.T) {
+	if _, err := LoadSnapshots(t.TempDir() + "/nope"); err == nil {
+		t.Errorf("LoadSnapshots() of a missing directory returned no error")
+	}
+}
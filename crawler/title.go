@@ -0,0 +1,213 @@
+package crawler
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// TitleMatch decides how Request.Titles are matched against movie titles.
+// Other than MatchRegex, titles are compared after normalizing case,
+// punctuation and leading articles, so "spider man" matches "The
+// Spider-Man".
+type TitleMatch int
+
+const (
+	// MatchContains matches movies containing the title at the start of a
+	// word, so "it" matches "It Ends With Us" but not "Wicked".
+	MatchContains TitleMatch = iota
+	// MatchExact matches movies with the same title.
+	MatchExact
+	// MatchRegex matches movies against the title as a case-insensitive
+	// regular expression.
+	MatchRegex
+	// MatchFuzzy is like MatchContains, but tolerates typos.
+	MatchFuzzy
+)
+
+// titlesKey returns req.Titles and how they're matched in a canonical form, so
+// that the same titles in a different order or case are considered the same
+// search.
+func (req *Request) titlesKey() string {
+	titles := make([]string, 0, len(req.Titles))
+	for _, title := range req.Titles {
+		titles = append(titles, strings.ToLower(title))
+	}
+	slices.Sort(titles)
+	return fmt.Sprintf("match=%d\n%s", req.TitleMatch, strings.Join(slices.Compact(titles), "\n"))
+}
+
+// A titleMatcher matches movie titles against Request.Titles.
+type titleMatcher struct {
+	mode   TitleMatch
+	titles []string
+	// normalized holds the normalized titles, or regexes the compiled
+	// titles for MatchRegex.
+	normalized []string
+	regexes    []*regexp.Regexp
+}
+
+func newTitleMatcher(req Request) (*titleMatcher, error) {
+	tm := &titleMatcher{mode: req.TitleMatch, titles: req.Titles}
+	for _, title := range req.Titles {
+		if tm.mode == MatchRegex {
+			regex, err := regexp.Compile("(?i)" + title)
+			if err != nil {
+				return nil, fmt.Errorf("bad title regex %q: %w", title, err)
+			}
+			tm.regexes = append(tm.regexes, regex)
+		}
+		tm.normalized = append(tm.normalized, normalizeTitle(title))
+	}
+	return tm, nil
+}
+
+// match returns the first title that movie matches. With no titles, every
+// movie matches.
+func (tm *titleMatcher) match(movie string) (string, bool) {
+	if len(tm.titles) == 0 {
+		return "", true
+	}
+	normalized := normalizeTitle(movie)
+	for i, title := range tm.titles {
+		var ok bool
+		switch tm.mode {
+		case MatchExact:
+			ok = normalized == tm.normalized[i]
+		case MatchRegex:
+			ok = tm.regexes[i].MatchString(movie)
+		case MatchFuzzy:
+			ok = containsWords(normalized, tm.normalized[i]) || fuzzyContains(normalized, tm.normalized[i])
+		default:
+			ok = containsWords(normalized, tm.normalized[i])
+		}
+		if ok {
+			return title, true
+		}
+	}
+	return "", false
+}
+
+// articles are dropped from the start of titles.
+var articles = map[string]bool{"the": true, "a": true, "an": true}
+
+// normalizeTitle lowercases title, turns punctuation into spaces and drops a
+// leading article.
+func normalizeTitle(title string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			builder.WriteRune(r)
+		case r == '\'' || r == '’':
+			// "Schindler's" is "schindlers", not "schindler s".
+		case r == '&':
+			builder.WriteString(" and ")
+		default:
+			builder.WriteRune(' ')
+		}
+	}
+	words := strings.Fields(builder.String())
+	if len(words) > 1 && articles[words[0]] {
+		words = words[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// containsWords returns whether want appears in title starting at a word.
+// Both are normalized.
+func containsWords(title, want string) bool {
+	return want != "" && strings.Contains(" "+title, " "+want)
+}
+
+// fuzzyContains returns whether some run of words in title is within a few
+// typos of want. Both are normalized.
+func fuzzyContains(title, want string) bool {
+	maxEdits := max(1, len([]rune(want))/4)
+	titleWords, wantWords := strings.Fields(title), strings.Fields(want)
+	for start := 0; start+len(wantWords) <= len(titleWords); start++ {
+		window := strings.Join(titleWords[start:start+len(wantWords)], " ")
+		if editDistance(window, want) <= maxEdits {
+			return true
+		}
+	}
+	return false
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		cur := make([]int, len(br)+1)
+		cur[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(br)]
+}
+
+// parentheticalRegex matches asides in titles like "(2024)" or "[Open
+// Caption]".
+var parentheticalRegex = regexp.MustCompile(`\s*[(\[][^)\]]*[)\]]`)
+
+// movieKey identifies a movie regardless of how a particular theater lists it,
+// e.g. "Sunny (2024)" and "Sunny" are the same movie.
+func movieKey(title string) string {
+	return normalizeTitle(parentheticalRegex.ReplaceAllString(title, ""))
+}
+
+// matchedMovies returns, for each of tm's titles, the distinct movies in
+// showings that it matched.
+func (tm *titleMatcher) matchedMovies(showings []Showing) map[string][]string {
+	matched := make(map[string][]string)
+	seen := make(map[string]map[string]bool)
+	for _, showing := range showings {
+		title, ok := tm.match(showing.Movie)
+		if !ok || len(tm.titles) == 0 {
+			continue
+		}
+		if seen[title] == nil {
+			seen[title] = make(map[string]bool)
+		}
+		if key := movieKey(showing.Movie); !seen[title][key] {
+			seen[title][key] = true
+			matched[title] = append(matched[title], showing.Movie)
+		}
+	}
+	for _, movies := range matched {
+		slices.Sort(movies)
+	}
+	return matched
+}
+
+// checkMatches logs which movies each title matched and, unless
+// req.AllMatches is set, returns an *AmbiguousTitleError if any title matched
+// more than one.
+func (tm *titleMatcher) checkMatches(req Request, showings []Showing) error {
+	logger := req.logger()
+	matched := tm.matchedMovies(showings)
+	for _, title := range tm.titles {
+		movies := matched[title]
+		switch {
+		case len(movies) == 0:
+			logger.Info("no movies matched title", "title", title)
+		case len(movies) > 1 && !req.AllMatches:
+			return &AmbiguousTitleError{Title: title, Movies: movies}
+		default:
+			logger.Info("matched movies", "title", title, "movies", movies)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package crawler
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestNormalizeTitle(t *testing.T) {
+	tcs := []struct {
+		title    string
+		expected string
+	}{
+		{title: "Sunny", expected: "sunny"},
+		{title: "The Spider-Man: Across the Spider-Verse", expected: "spider man across the spider verse"},
+		{title: "Schindler's List", expected: "schindlers list"},
+		{title: "A Quiet Place", expected: "quiet place"},
+		{title: "Fast & Furious", expected: "fast and furious"},
+		{title: "The", expected: "the"},
+		{title: "  ", expected: ""},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.title, func(t *testing.T) {
+			if got := normalizeTitle(tc.title); got != tc.expected {
+				t.Errorf("normalizeTitle(%q) = %q, want %q", tc.title, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestMatchTitle(t *testing.T) {
+	tcs := []struct {
+		name     string
+		mode     TitleMatch
+		titles   []string
+		movie    string
+		expected string
+	}{{
+		name:     "contains",
+		titles:   []string{"sunny", "The Room"},
+		movie:    "Sunny (2024)",
+		expected: "sunny",
+	}, {
+		name:     "contains second title",
+		titles:   []string{"sunny", "The Room"},
+		movie:    "THE ROOMMATE",
+		expected: "The Room",
+	}, {
+		name:   "contains only at word starts",
+		titles: []string{"it"},
+		movie:  "Wicked",
+	}, {
+		name:     "contains ignores punctuation",
+		titles:   []string{"spider man"},
+		movie:    "The Spider-Man",
+		expected: "spider man",
+	}, {
+		name:   "no match",
+		titles: []string{"sunny"},
+		movie:  "Cloudy",
+	}, {
+		name:     "exact",
+		mode:     MatchExact,
+		titles:   []string{"room"},
+		movie:    "The Room",
+		expected: "room",
+	}, {
+		name:   "exact rejects longer titles",
+		mode:   MatchExact,
+		titles: []string{"the room"},
+		movie:  "The Roommate",
+	}, {
+		name:     "regex",
+		mode:     MatchRegex,
+		titles:   []string{`^it$`},
+		movie:    "IT",
+		expected: `^it$`,
+	}, {
+		name:   "regex no match",
+		mode:   MatchRegex,
+		titles: []string{`^it$`},
+		movie:  "It Ends With Us",
+	}, {
+		name:     "fuzzy typo",
+		mode:     MatchFuzzy,
+		titles:   []string{"gladiatr"},
+		movie:    "Gladiator II",
+		expected: "gladiatr",
+	}, {
+		name:     "fuzzy multiple words",
+		mode:     MatchFuzzy,
+		titles:   []string{"wild robt"},
+		movie:    "The Wild Robot",
+		expected: "wild robt",
+	}, {
+		name:   "fuzzy too different",
+		mode:   MatchFuzzy,
+		titles: []string{"gladiator"},
+		movie:  "Venom",
+	}}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			tm, err := newTitleMatcher(Request{Titles: tc.titles, TitleMatch: tc.mode})
+			if err != nil {
+				t.Fatalf("newTitleMatcher() returned error: %v", err)
+			}
+			got, ok := tm.match(tc.movie)
+			if ok != (tc.expected != "") || got != tc.expected {
+				t.Errorf("match(%q) = (%q, %t), want %q", tc.movie, got, ok, tc.expected)
+			}
+		})
+	}
+
+	if _, err := newTitleMatcher(Request{Titles: []string{"(unclosed"}, TitleMatch: MatchRegex}); err == nil {
+		t.Errorf("newTitleMatcher() with a bad regex returned no error")
+	}
+}
+
+func TestTitlesKey(t *testing.T) {
+	req := Request{Titles: []string{"sunny", "The Room"}}
+	reordered := Request{Titles: []string{"the room", "Sunny", "sunny"}}
+	if req.titlesKey() != reordered.titlesKey() {
+		t.Errorf("titlesKey() differs for the same titles: %q vs %q", req.titlesKey(), reordered.titlesKey())
+	}
+	exact := Request{Titles: req.Titles, TitleMatch: MatchExact}
+	if req.titlesKey() == exact.titlesKey() {
+		t.Errorf("titlesKey() is the same for different kinds of match")
+	}
+}
+
+func TestCheckMatches(t *testing.T) {
+	showings := []Showing{
+		{Movie: "It Ends With Us"},
+		{Movie: "Sunny"},
+		{Movie: "Sunny (2024)"},
+		{Movie: "It"},
+		{Movie: "It Ends With Us"},
+	}
+
+	req := Request{Titles: []string{"sunny"}}
+	tm, err := newTitleMatcher(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tm.checkMatches(req, showings); err != nil {
+		t.Errorf("checkMatches() for one movie listed two ways returned error: %v", err)
+	}
+
+	req = Request{Titles: []string{"sunny", "it"}}
+	tm, err = newTitleMatcher(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ambiguous *AmbiguousTitleError
+	if err := tm.checkMatches(req, showings); !errors.As(err, &ambiguous) {
+		t.Fatalf("checkMatches() = %v, want an *AmbiguousTitleError", err)
+	}
+	if ambiguous.Title != "it" || !slices.Equal(ambiguous.Movies, []string{"It", "It Ends With Us"}) {
+		t.Errorf("checkMatches() returned %+v", ambiguous)
+	}
+
+	req.AllMatches = true
+	if err := tm.checkMatches(req, showings); err != nil {
+		t.Errorf("checkMatches() with AllMatches returned error: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kevinGC/mseater/crawler"
+)
+
+// runEvaluate implements `mseater evaluate <snapshot-dir>`, which checks seat
+// map snapshots saved via --snapshot-dir against new criteria. It doesn't
+// touch the network.
+func runEvaluate(args []string) error {
+	var (
+		numSeats int
+		link     bool
+		showBad  bool
+		sortBy   resultSort
+	)
+	sortBy.Set("theater")
+
+	flags := flag.NewFlagSet("evaluate", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s evaluate <snapshot-dir> [flags]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.IntVar(&numSeats, "num-seats", 2, "The number of contiguous seats to find.")
+	flags.BoolVar(&link, "link", false, "Whether to show links in showtime results.")
+	flags.BoolVar(&showBad, "show-bad", false, "Whether to also output bad showtimes.")
+	flags.Var(&sortBy, "sort", `How to sort each movie's results: "theater", "time" or "distance".`)
+
+	dir, err := parseEvaluateArgs(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if numSeats < 1 {
+		return fmt.Errorf("too few seats specified: must be at least 1")
+	}
+
+	snapshots, err := crawler.LoadSnapshots(dir)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		return fmt.Errorf("no snapshots found in %q", dir)
+	}
+
+	req := crawler.Request{NumSeats: numSeats}
+	var result crawler.Result
+	for _, snapshot := range snapshots {
+		result.Add(snapshot.Evaluate(req))
+	}
+	printResult(result, link, showBad, sortBy)
+	return nil
+}
+
+// parseEvaluateArgs parses args with flags and returns the snapshot directory,
+// which may come before or after the flags.
+func parseEvaluateArgs(flags *flag.FlagSet, args []string) (string, error) {
+	var dir string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		dir, args = args[0], args[1:]
+	}
+	if err := flags.Parse(args); err != nil {
+		return "", err
+	}
+	switch {
+	case dir == "" && flags.NArg() == 1:
+		dir = flags.Arg(0)
+	case dir == "" || flags.NArg() != 0:
+		return "", fmt.Errorf("expected exactly one snapshot directory")
+	}
+	return dir, nil
+}
@@ -2,12 +2,14 @@ module github.com/kevinGC/mseater
 
 go 1.23
 
-require github.com/playwright-community/playwright-go v0.4902.0
+require (
+	github.com/playwright-community/playwright-go v0.4902.0
+	golang.org/x/exp v0.0.0-20240506185415-9bf2ced13842
+)
 
 require (
 	github.com/deckarep/golang-set/v2 v2.7.0 // indirect
 	github.com/go-jose/go-jose/v3 v3.0.3 // indirect
 	github.com/go-stack/stack v1.8.1 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
-	golang.org/x/exp v0.0.0-20240506185415-9bf2ced13842 // indirect
 )
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kevinGC/mseater/crawler"
+)
+
+// runMovies implements `mseater movies`, which lists what's playing nearby so
+// that a title can be chosen before the much slower seat search.
+func runMovies(args []string) error {
+	var (
+		titles      stringList
+		dates       dateList
+		zip         zip
+		timeout     time.Duration
+		maxTheaters int
+		cacheDir    string
+		noCache     bool
+
+		requestInterval durationRange
+		browser         string
+		headful         bool
+		browserPath     string
+		cdpEndpoint     string
+		proxy           string
+	)
+	dates.Set("today")
+	requestInterval.Set("15-25")
+
+	flags := flag.NewFlagSet("movies", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s movies --zip <zip> [flags]\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Var(&zip, "zip", "Zip code to search near.")
+	flags.Var(&dates, "date", `Days to search, as for the main command, e.g. "friday" or "this-weekend".`)
+	flags.Var(&titles, "title", "Only list movies matching these titles.")
+	flags.IntVar(&maxTheaters, "max-theaters", 30, "The max number of theaters to search, nearest first. 0 means all of them.")
+	flags.DurationVar(&timeout, "timeout", 0 /* unlimited */, "The timeout for searching.")
+	flags.StringVar(&cacheDir, "cache-dir", defaultCacheDir(), "Where to cache search results. Caching is on by default; use --no-cache to turn it off.")
+	flags.BoolVar(&noCache, "no-cache", false, "Whether to skip the cache and search fresh.")
+	flags.Var(&requestInterval, "request-interval", "The interval, in seconds, between making HTTP requests, as for the main command.")
+	flags.StringVar(&browser, "browser", crawler.Chromium, fmt.Sprintf("The browser engine to use: %s, %s or %s.", crawler.Chromium, crawler.Firefox, crawler.WebKit))
+	flags.BoolVar(&headful, "headful", false, "Whether to show the browser window.")
+	flags.StringVar(&browserPath, "browser-path", "", "Path to a browser executable to use instead of the one installed by Playwright.")
+	flags.StringVar(&cdpEndpoint, "cdp-endpoint", "", "Attach to an already running Chromium at this CDP endpoint instead of launching a browser.")
+	flags.StringVar(&proxy, "proxy", "", "Proxy URL to crawl through. Defaults to $HTTPS_PROXY, skipping hosts in $NO_PROXY.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 0 {
+		return fmt.Errorf("unexpected arguments: %q", flags.Args())
+	}
+	if zip.zip == "" {
+		return fmt.Errorf("no zip code provided (use --zip)")
+	}
+	if maxTheaters < 0 {
+		return fmt.Errorf("--max-theaters must be at least 0")
+	}
+
+	ctx := context.Background()
+	cancel := func() {}
+	if timeout != 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+
+	clientProxy, err := resolveProxy(proxy, os.Getenv)
+	if err != nil {
+		return err
+	}
+	var cacheOpts *crawler.CacheOptions
+	if !noCache && cacheDir != "" {
+		opts := crawler.DefaultCacheOptions(cacheDir)
+		cacheOpts = &opts
+	}
+	client, err := crawler.NewClient(crawler.ClientOptions{
+		Browser:        browser,
+		Headful:        headful,
+		ExecutablePath: browserPath,
+		CDPEndpoint:    cdpEndpoint,
+		Proxy:          clientProxy,
+		Cache:          cacheOpts,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start browser: %w", err)
+	}
+	defer func() {
+		if err := client.Close(); err != nil {
+			slog.Info("failed to close browser", "err", err)
+		}
+	}()
+
+	movies, err := client.Movies(ctx, crawler.Request{
+		Titles:          titles.values,
+		Dates:           dates.dates,
+		Zip:             zip.zip,
+		MaxTheaters:     maxTheaters,
+		RequestInterval: requestInterval.DurationRange,
+		Logger:          slog.Default(),
+	})
+	if err != nil {
+		return explain(err)
+	}
+	if len(movies) == 0 {
+		return fmt.Errorf("no movies found")
+	}
+	fmt.Print(formatMovieList(movies, len(dates.dates) > 1))
+	return nil
+}
+
+// formatMovieList formats movies one per line. withDays includes the day of
+// the earliest and latest showtimes.
+func formatMovieList(movies []crawler.Movie, withDays bool) string {
+	var builder strings.Builder
+	writer := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "MOVIE\tTHEATERS\tSHOWINGS\tTIMES\tFORMATS\n")
+	for _, movie := range movies {
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%s - %s\t%s\n", movie.Title, movie.Theaters, movie.Showings,
+			formatWhen(movie.Earliest, withDays), formatWhen(movie.Latest, withDays), strings.Join(movie.Formats, ", "))
+	}
+	writer.Flush()
+	return builder.String()
+}
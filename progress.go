@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/kevinGC/mseater/crawler"
+)
+
+// progress is a crawler.Observer that shows a live, single-line summary of a
+// crawl on a terminal.
+type progress struct {
+	out      io.Writer
+	interval crawler.DurationRange
+	// shown is whether a line is currently displayed.
+	shown bool
+
+	theaters int
+	found    int
+	queued   int
+	good     int
+	bad      int
+	failed   int
+	retries  int
+}
+
+func newProgress(out io.Writer, interval crawler.DurationRange) *progress {
+	return &progress{out: out, interval: interval}
+}
+
+// Observe implements crawler.Observer.
+func (pr *progress) Observe(ev crawler.Event) {
+	switch ev := ev.(type) {
+	case crawler.SearchStartedEvent:
+		pr.show("Searching for showings...")
+		return
+	case crawler.TheaterParsedEvent:
+		pr.theaters++
+		pr.found += ev.NumShowings
+		pr.show(fmt.Sprintf("Searching: found %d showings at %d theaters...", pr.found, pr.theaters))
+		return
+	case crawler.ShowingQueuedEvent:
+		pr.queued++
+	case crawler.SeatMapLoadedEvent:
+		if ev.Showing.Verdict == crawler.VerdictGood {
+			pr.good++
+		} else {
+			pr.bad++
+		}
+	case crawler.RetryEvent:
+		pr.retries++
+	case crawler.ShowingFailedEvent:
+		pr.failed++
+	case crawler.DoneEvent:
+		pr.clear()
+		return
+	}
+	pr.show(fmt.Sprintf("Checked %d of %d showings (%d good, %d failed, %d retries), about %s left",
+		pr.checked(), pr.queued, pr.good, pr.failed, pr.retries, pr.eta()))
+}
+
+func (pr *progress) checked() int {
+	return pr.good + pr.bad + pr.failed
+}
+
+// eta estimates the time left in the crawl. Page loads are dwarfed by the
+// wait between requests, so assume each remaining showing takes the average
+// interval.
+func (pr *progress) eta() time.Duration {
+	remaining := time.Duration(pr.queued - pr.checked())
+	return (remaining * (pr.interval.Lower + pr.interval.Upper) / 2).Round(time.Second)
+}
+
+// show replaces the displayed line with line.
+func (pr *progress) show(line string) {
+	fmt.Fprintf(pr.out, "\r\033[K%s", line)
+	pr.shown = true
+}
+
+// clear erases the displayed line, if any, so other output can be printed. It's
+// safe to call on a nil progress.
+func (pr *progress) clear() {
+	if pr != nil && pr.shown {
+		fmt.Fprintf(pr.out, "\r\033[K")
+		pr.shown = false
+	}
+}
+
+// A clearingHandler wraps a Handler, clearing the progress line before each
+// record so that logs aren't tangled up with it.
+type clearingHandler struct {
+	prog    *progress
+	handler slog.Handler
+}
+
+func newClearingHandler(prog *progress, h slog.Handler) *clearingHandler {
+	return &clearingHandler{prog: prog, handler: h}
+}
+
+// Enabled implements Handler.Enabled.
+func (h *clearingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements Handler.Handle.
+func (h *clearingHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.prog.clear()
+	return h.handler.Handle(ctx, r)
+}
+
+// WithAttrs implements Handler.WithAttrs.
+func (h *clearingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return newClearingHandler(h.prog, h.handler.WithAttrs(attrs))
+}
+
+// WithGroup implements Handler.WithGroup.
+func (h *clearingHandler) WithGroup(name string) slog.Handler {
+	return newClearingHandler(h.prog, h.handler.WithGroup(name))
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}